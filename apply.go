@@ -0,0 +1,174 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import "fmt"
+
+// ApplyError represents a hunk that cannot be applied to the source lines.
+type ApplyError struct {
+	// Hunk is the index of the hunk.
+	Hunk int
+	// Line is the 1-based source line number at which the hunk failed.
+	Line int
+	Msg  string
+}
+
+// Error implements the error interface.
+func (e *ApplyError) Error() string {
+	return fmt.Sprintf("hunk #%d: line %d: %s", e.Hunk+1, e.Line, e.Msg)
+}
+
+// hunkStart returns the 0-based source index at which a hunk starts.
+// An empty source range starts after the line before it.
+func hunkStart(h Hunk) int {
+	if h.SrcLines == 0 {
+		return h.SrcStart
+	}
+	return h.SrcStart - 1
+}
+
+// matchHunk matches the equal and deleted lines of a hunk against the
+// source lines starting at index i. It returns the index after the last
+// matched line, or an error if the lines do not match.
+func matchHunk(src []string, diffs []DiffLine, i int) (int, *ApplyError) {
+	for _, l := range diffs {
+		if l.Type == Insert {
+			continue
+		}
+		if i >= len(src) {
+			return 0, &ApplyError{Line: i + 1, Msg: fmt.Sprintf("expected %q, found end of file", l.Text)}
+		}
+		if src[i] != l.Text {
+			return 0, &ApplyError{Line: i + 1, Msg: fmt.Sprintf("expected %q, found %q", l.Text, src[i])}
+		}
+		i++
+	}
+	return i, nil
+}
+
+// appendHunk appends the equal and inserted lines of a hunk to dst.
+func appendHunk(dst []string, diffs []DiffLine) []string {
+	for _, l := range diffs {
+		if l.Type != Delete {
+			dst = append(dst, l.Text)
+		}
+	}
+	return dst
+}
+
+// Apply applies hunks to source lines and returns the destination lines.
+// Hunks must be in order and must not overlap. The equal and deleted lines
+// of each hunk must match the source lines at the hunk's SrcStart, otherwise
+// an *ApplyError is returned.
+func Apply(src []string, hunks []Hunk) ([]string, error) {
+	dst := make([]string, 0, len(src))
+	pos := 0
+	for k, h := range hunks {
+		start := hunkStart(h)
+		if start < pos || start > len(src) {
+			return nil, &ApplyError{Hunk: k, Line: h.SrcStart, Msg: "hunk is out of order or out of range"}
+		}
+		end, err := matchHunk(src, h.Diffs, start)
+		if err != nil {
+			err.Hunk = k
+			return nil, err
+		}
+		dst = append(dst, src[pos:start]...)
+		dst = appendHunk(dst, h.Diffs)
+		pos = end
+	}
+	return append(dst, src[pos:]...), nil
+}
+
+// ApplyOptions represents the options for ApplyWithOptions.
+type ApplyOptions struct {
+	// MaxOffset is the maximum number of lines before or after its expected
+	// position that a hunk is searched for. Zero or less applies hunks only
+	// at their expected position.
+	MaxOffset int
+	// Fuzz is the maximum number of leading and trailing context lines of a
+	// hunk that may be ignored when it does not match (patch --fuzz). Zero or
+	// less ignores no context lines.
+	Fuzz int
+}
+
+// HunkResult represents the result of applying a single hunk.
+type HunkResult struct {
+	// Applied reports whether the hunk was applied.
+	Applied bool
+	// Offset is the number of lines from the hunk's SrcStart at which it was applied.
+	Offset int
+	// Fuzz is the number of leading and trailing context lines that were ignored.
+	Fuzz int
+}
+
+// ApplyResult represents the result of ApplyWithOptions.
+type ApplyResult struct {
+	// Lines are the destination lines.
+	Lines []string
+	// Hunks are the results of each hunk.
+	Hunks []HunkResult
+	// Rejects are the hunks that were not applied.
+	Rejects []Hunk
+}
+
+// trimContext returns the diff lines of a hunk without up to fuzz leading
+// and trailing equal lines, and the number of leading lines removed.
+func trimContext(diffs []DiffLine, fuzz int) ([]DiffLine, int) {
+	head := 0
+	for head < fuzz && head < len(diffs) && diffs[head].Type == Equal {
+		head++
+	}
+	tail := 0
+	for tail < fuzz && tail < len(diffs)-head && diffs[len(diffs)-1-tail].Type == Equal {
+		tail++
+	}
+	return diffs[head : len(diffs)-tail], head
+}
+
+// ApplyWithOptions applies hunks to source lines, searching for hunks that
+// do not match at their expected position, as GNU patch does. Each hunk is
+// first searched for with all of its context, at increasing offsets from
+// its SrcStart adjusted by the offset of the previous applied hunk. If it is
+// not found, up to Fuzz leading and trailing context lines are ignored in
+// turn. Hunks that cannot be applied are returned as rejects.
+func ApplyWithOptions(src []string, hunks []Hunk, opts ApplyOptions) ApplyResult {
+	res := ApplyResult{
+		Lines: make([]string, 0, len(src)),
+		Hunks: make([]HunkResult, len(hunks)),
+	}
+	maxOffset, maxFuzz := max(opts.MaxOffset, 0), max(opts.Fuzz, 0)
+	pos, lastOffset := 0, 0
+	for k, h := range hunks {
+		applied := false
+		for fuzz := 0; fuzz <= maxFuzz && !applied; fuzz++ {
+			diffs, head := trimContext(h.Diffs, fuzz)
+			start := hunkStart(h) + head
+			for i := 0; i <= 2*maxOffset; i++ {
+				// Search offsets 0, -1, 1, -2, 2 and so on.
+				offset := lastOffset + (i+1)/2
+				if i%2 == 1 {
+					offset = lastOffset - (i+1)/2
+				}
+				at := start + offset
+				if at < pos || at > len(src) {
+					continue
+				}
+				end, err := matchHunk(src, diffs, at)
+				if err != nil {
+					continue
+				}
+				res.Lines = append(res.Lines, src[pos:at]...)
+				res.Lines = appendHunk(res.Lines, diffs)
+				res.Hunks[k] = HunkResult{Applied: true, Offset: offset, Fuzz: fuzz}
+				pos, lastOffset = end, offset
+				applied = true
+				break
+			}
+		}
+		if !applied {
+			res.Rejects = append(res.Rejects, h)
+		}
+	}
+	res.Lines = append(res.Lines, src[pos:]...)
+	return res
+}
@@ -0,0 +1,289 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	type args struct {
+		src   []string
+		hunks []Hunk
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr *ApplyError
+	}{
+		{
+			name: "Test no hunks",
+			args: args{
+				src:   []string{"a", "b"},
+				hunks: nil,
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "Test hunks with context",
+			args: args{
+				src: []string{"a", "b", "c", "d", "e", "f"},
+				hunks: []Hunk{
+					{
+						Diffs: []DiffLine{
+							{Text: "a", Type: Equal},
+							{Text: "b", Type: Delete},
+							{Text: "x", Type: Insert},
+							{Text: "c", Type: Equal},
+						},
+						SrcStart: 1,
+						SrcLines: 3,
+						DstStart: 1,
+						DstLines: 3,
+					},
+					{
+						Diffs: []DiffLine{
+							{Text: "e", Type: Equal},
+							{Text: "y", Type: Insert},
+						},
+						SrcStart: 5,
+						SrcLines: 1,
+						DstStart: 5,
+						DstLines: 2,
+					},
+				},
+			},
+			want: []string{"a", "x", "c", "d", "e", "y", "f"},
+		},
+		{
+			name: "Test empty source range",
+			args: args{
+				src: []string{"a", "b"},
+				hunks: []Hunk{
+					{
+						Diffs:    []DiffLine{{Text: "x", Type: Insert}},
+						SrcStart: 1,
+						SrcLines: 0,
+						DstStart: 2,
+						DstLines: 1,
+					},
+				},
+			},
+			want: []string{"a", "x", "b"},
+		},
+		{
+			name: "Test mismatched line",
+			args: args{
+				src: []string{"a", "b", "c"},
+				hunks: []Hunk{
+					{
+						Diffs: []DiffLine{
+							{Text: "b", Type: Equal},
+							{Text: "z", Type: Delete},
+						},
+						SrcStart: 2,
+						SrcLines: 2,
+						DstStart: 2,
+						DstLines: 1,
+					},
+				},
+			},
+			wantErr: &ApplyError{Hunk: 0, Line: 3, Msg: `expected "z", found "c"`},
+		},
+		{
+			name: "Test hunk past the end of the source",
+			args: args{
+				src: []string{"a"},
+				hunks: []Hunk{
+					{
+						Diffs: []DiffLine{
+							{Text: "a", Type: Equal},
+							{Text: "b", Type: Delete},
+						},
+						SrcStart: 1,
+						SrcLines: 2,
+						DstStart: 1,
+						DstLines: 1,
+					},
+				},
+			},
+			wantErr: &ApplyError{Hunk: 0, Line: 2, Msg: `expected "b", found end of file`},
+		},
+		{
+			name: "Test overlapping hunks",
+			args: args{
+				src: []string{"a", "b"},
+				hunks: []Hunk{
+					{
+						Diffs:    []DiffLine{{Text: "b", Type: Delete}},
+						SrcStart: 2,
+						SrcLines: 1,
+						DstStart: 2,
+						DstLines: 0,
+					},
+					{
+						Diffs:    []DiffLine{{Text: "a", Type: Delete}},
+						SrcStart: 1,
+						SrcLines: 1,
+						DstStart: 1,
+						DstLines: 0,
+					},
+				},
+			},
+			wantErr: &ApplyError{Hunk: 1, Line: 1, Msg: "hunk is out of order or out of range"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply(tt.args.src, tt.args.hunks)
+			if tt.wantErr != nil {
+				var aerr *ApplyError
+				if !errors.As(err, &aerr) || !reflect.DeepEqual(aerr, tt.wantErr) {
+					t.Errorf("Apply() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestApplyRoundTrip tests that applying the hunks of a diff to the
+// source returns the destination.
+func TestApplyRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 500; n++ {
+		a := randomLines(r, r.Intn(40), 1+r.Intn(10))
+		b := randomLines(r, r.Intn(40), 1+r.Intn(10))
+		ctx := r.Intn(4)
+		got, err := Apply(a, Hunks(Diff(a, b), ctx, ctx))
+		if err != nil {
+			t.Fatalf("Apply(%v) error = %v", a, err)
+		}
+		if len(got) != len(b) || (len(b) > 0 && !reflect.DeepEqual(got, b)) {
+			t.Fatalf("Apply(%v, Diff(%v)) = %v, want %v", a, b, got, b)
+		}
+	}
+}
+
+func TestApplyWithOptions(t *testing.T) {
+	hunks := []Hunk{
+		{
+			Diffs: []DiffLine{
+				{Text: "a", Type: Equal},
+				{Text: "b", Type: Delete},
+				{Text: "x", Type: Insert},
+				{Text: "c", Type: Equal},
+			},
+			SrcStart: 1,
+			SrcLines: 3,
+			DstStart: 1,
+			DstLines: 3,
+		},
+		{
+			Diffs: []DiffLine{
+				{Text: "e", Type: Equal},
+				{Text: "f", Type: Delete},
+				{Text: "g", Type: Equal},
+			},
+			SrcStart: 5,
+			SrcLines: 3,
+			DstStart: 5,
+			DstLines: 2,
+		},
+	}
+
+	type args struct {
+		src  []string
+		opts ApplyOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want ApplyResult
+	}{
+		{
+			name: "Test exact positions",
+			args: args{
+				src:  []string{"a", "b", "c", "d", "e", "f", "g"},
+				opts: ApplyOptions{},
+			},
+			want: ApplyResult{
+				Lines: []string{"a", "x", "c", "d", "e", "g"},
+				Hunks: []HunkResult{{Applied: true}, {Applied: true}},
+			},
+		},
+		{
+			name: "Test negative options apply at exact positions",
+			args: args{
+				src:  []string{"a", "b", "c", "d", "e", "f", "g"},
+				opts: ApplyOptions{MaxOffset: -1, Fuzz: -1},
+			},
+			want: ApplyResult{
+				Lines: []string{"a", "x", "c", "d", "e", "g"},
+				Hunks: []HunkResult{{Applied: true}, {Applied: true}},
+			},
+		},
+		{
+			name: "Test offsets",
+			args: args{
+				src:  []string{"0", "0", "a", "b", "c", "d", "e", "f", "g"},
+				opts: ApplyOptions{MaxOffset: 2},
+			},
+			want: ApplyResult{
+				Lines: []string{"0", "0", "a", "x", "c", "d", "e", "g"},
+				Hunks: []HunkResult{{Applied: true, Offset: 2}, {Applied: true, Offset: 2}},
+			},
+		},
+		{
+			name: "Test offsets beyond the maximum",
+			args: args{
+				src:  []string{"0", "0", "a", "b", "c", "d", "e", "f", "g"},
+				opts: ApplyOptions{MaxOffset: 1},
+			},
+			want: ApplyResult{
+				Lines:   []string{"0", "0", "a", "b", "c", "d", "e", "f", "g"},
+				Hunks:   []HunkResult{{}, {}},
+				Rejects: hunks,
+			},
+		},
+		{
+			name: "Test fuzz",
+			args: args{
+				src:  []string{"z", "b", "c", "d", "e", "f", "y"},
+				opts: ApplyOptions{Fuzz: 1},
+			},
+			want: ApplyResult{
+				Lines: []string{"z", "x", "c", "d", "e", "y"},
+				Hunks: []HunkResult{{Applied: true, Fuzz: 1}, {Applied: true, Fuzz: 1}},
+			},
+		},
+		{
+			name: "Test rejects",
+			args: args{
+				src:  []string{"z", "b", "c", "d", "e", "f", "y"},
+				opts: ApplyOptions{MaxOffset: 10},
+			},
+			want: ApplyResult{
+				Lines:   []string{"z", "b", "c", "d", "e", "f", "y"},
+				Hunks:   []HunkResult{{}, {}},
+				Rejects: hunks,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApplyWithOptions(tt.args.src, hunks, tt.args.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"strings"
+	"unicode"
+)
+
+// colorReset resets all ANSI text attributes.
+const colorReset = "\x1b[m"
+
+// ColorScheme represents the ANSI escape sequences used to color diff text.
+// Text with an empty sequence is not colored.
+type ColorScheme struct {
+	// Header colors file headers.
+	Header string
+	// HunkHeader colors the line ranges of hunk headers.
+	HunkHeader string
+	// Equal colors context lines.
+	Equal string
+	// Insert colors inserted lines.
+	Insert string
+	// Delete colors deleted lines.
+	Delete string
+	// MovedInsert colors inserted lines that were moved.
+	MovedInsert string
+	// MovedDelete colors deleted lines that were moved.
+	MovedDelete string
+	// Whitespace colors trailing white space on inserted lines.
+	Whitespace string
+	// Highlight starts highlighting the changed segments of a line.
+	Highlight string
+	// HighlightOff stops highlighting the changed segments of a line.
+	HighlightOff string
+}
+
+// DefaultColorScheme returns a ColorScheme with the default colors of git diff.
+// Changed segments of lines are highlighted in reverse video.
+func DefaultColorScheme() *ColorScheme {
+	return &ColorScheme{
+		Header:       "\x1b[1m",
+		HunkHeader:   "\x1b[36m",
+		Insert:       "\x1b[32m",
+		Delete:       "\x1b[31m",
+		MovedInsert:  "\x1b[1;36m",
+		MovedDelete:  "\x1b[1;35m",
+		Whitespace:   "\x1b[41m",
+		Highlight:    "\x1b[7m",
+		HighlightOff: "\x1b[27m",
+	}
+}
+
+// colorize returns text wrapped in a color, if the color is not empty.
+func colorize(color, text string) string {
+	if len(color) == 0 || len(text) == 0 {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// lineColor returns the color of a diff line.
+func (c *ColorScheme) lineColor(l DiffLine) string {
+	switch {
+	case l.Type == Insert && l.Move != 0 && len(c.MovedInsert) > 0:
+		return c.MovedInsert
+	case l.Type == Delete && l.Move != 0 && len(c.MovedDelete) > 0:
+		return c.MovedDelete
+	case l.Type == Insert:
+		return c.Insert
+	case l.Type == Delete:
+		return c.Delete
+	default:
+		return c.Equal
+	}
+}
+
+// highlightSegments returns the first n bytes of the text of a line's
+// segments, with changed segments highlighted.
+func (c *ColorScheme) highlightSegments(segments []Segment, n int) string {
+	var sb strings.Builder
+	for _, s := range segments {
+		text := s.Text[:min(len(s.Text), n)]
+		n -= len(text)
+		if s.Type != Equal && len(text) > 0 {
+			sb.WriteString(c.Highlight)
+			sb.WriteString(text)
+			sb.WriteString(c.HighlightOff)
+		} else {
+			sb.WriteString(text)
+		}
+	}
+	return sb.String()
+}
+
+// line returns a diff line with its symbol, colored.
+func (c *ColorScheme) line(l DiffLine) string {
+	if l.Type == Equal && len(l.Text) == 0 {
+		return ""
+	}
+
+	// Separate trailing white space on inserted lines.
+	body, trailing := l.Text, ""
+	if l.Type == Insert && len(c.Whitespace) > 0 {
+		body = strings.TrimRightFunc(l.Text, unicode.IsSpace)
+		trailing = l.Text[len(body):]
+	}
+	if len(l.Segments) > 0 && len(c.Highlight) > 0 {
+		body = c.highlightSegments(l.Segments, len(body))
+	}
+	return colorize(c.lineColor(l), typeSymbol(l.Type)+body) + colorize(c.Whitespace, trailing)
+}
@@ -0,0 +1,140 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"testing"
+)
+
+func TestDiffTextWithOptions(t *testing.T) {
+	type args struct {
+		diffs []DiffLine
+		opts  DiffTextOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "No color",
+			args: args{
+				diffs: []DiffLine{
+					{Type: Equal, Text: "a"},
+					{Type: Delete, Text: "b"},
+					{Type: Insert, Text: "c"},
+				},
+			},
+			want: " a\n-b\n+c",
+		},
+		{
+			name: "Default colors",
+			args: args{
+				diffs: []DiffLine{
+					{Type: Equal, Text: "a"},
+					{Type: Equal, Text: ""},
+					{Type: Delete, Text: "b"},
+					{Type: Insert, Text: "c"},
+				},
+				opts: DiffTextOptions{Color: DefaultColorScheme()},
+			},
+			want: " a\n\n\x1b[31m-b\x1b[m\n\x1b[32m+c\x1b[m",
+		},
+		{
+			name: "Trailing white space on inserted lines",
+			args: args{
+				diffs: []DiffLine{
+					{Type: Delete, Text: "a \t"},
+					{Type: Insert, Text: "b \t"},
+					{Type: Insert, Text: "  "},
+				},
+				opts: DiffTextOptions{Color: DefaultColorScheme()},
+			},
+			want: "\x1b[31m-a \t\x1b[m\n" +
+				"\x1b[32m+b\x1b[m\x1b[41m \t\x1b[m\n" +
+				"\x1b[32m+\x1b[m\x1b[41m  \x1b[m",
+		},
+		{
+			name: "Changed segments",
+			args: args{
+				diffs: []DiffLine{
+					{
+						Type: Delete,
+						Text: "the quick fox",
+						Segments: []Segment{
+							{Type: Equal, Text: "the "},
+							{Type: Delete, Text: "quick"},
+							{Type: Equal, Text: " fox"},
+						},
+					},
+					{
+						Type: Insert,
+						Text: "the slow fox ",
+						Segments: []Segment{
+							{Type: Equal, Text: "the "},
+							{Type: Insert, Text: "slow"},
+							{Type: Equal, Text: " fox "},
+						},
+					},
+				},
+				opts: DiffTextOptions{Color: DefaultColorScheme()},
+			},
+			want: "\x1b[31m-the \x1b[7mquick\x1b[27m fox\x1b[m\n" +
+				"\x1b[32m+the \x1b[7mslow\x1b[27m fox\x1b[m\x1b[41m \x1b[m",
+		},
+		{
+			name: "Moved lines",
+			args: args{
+				diffs: []DiffLine{
+					{Type: Delete, Text: "a", Move: 1},
+					{Type: Equal, Text: "b"},
+					{Type: Insert, Text: "a", Move: 1},
+				},
+				opts: DiffTextOptions{Color: DefaultColorScheme()},
+			},
+			want: "\x1b[1;35m-a\x1b[m\n b\n\x1b[1;36m+a\x1b[m",
+		},
+		{
+			name: "Custom colors",
+			args: args{
+				diffs: []DiffLine{
+					{Type: Equal, Text: "a"},
+					{Type: Delete, Text: "b", Move: 1},
+					{Type: Insert, Text: "c "},
+				},
+				opts: DiffTextOptions{Color: &ColorScheme{Equal: "\x1b[2m", Delete: "\x1b[35m"}},
+			},
+			want: "\x1b[2m a\x1b[m\n\x1b[35m-b\x1b[m\n+c ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffTextWithOptions(tt.args.diffs, tt.args.opts); got != tt.want {
+				t.Errorf("DiffTextWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffTextWithOptionsColor(t *testing.T) {
+	diffs := []DiffLine{
+		{Type: Equal, Text: "a"},
+		{Type: Delete, Text: "b"},
+		{Type: Insert, Text: "c"},
+	}
+	hunks := Hunks(diffs, 1, 1)
+	hunks[0].Section = "func main()"
+
+	want := "\x1b[1m--- a.txt\x1b[m\n" +
+		"\x1b[1m+++ b.txt\x1b[m\n" +
+		"\x1b[36m@@ -1,2 +1,2 @@\x1b[m func main()\n" +
+		" a\n" +
+		"\x1b[31m-b\x1b[m\n" +
+		"\x1b[32m+c\x1b[m"
+
+	if got := UnifiedHunksText(
+		hunks,
+		UnifiedDiffOptions{SrcHeader: "a.txt", DstHeader: "b.txt", Color: DefaultColorScheme()},
+	); got != want {
+		t.Errorf("UnifiedHunksText() = %q, want %q", got, want)
+	}
+}
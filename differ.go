@@ -0,0 +1,82 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Differ is the interface implemented by diff algorithms.
+type Differ interface {
+	// Diff returns the diff of two slices of strings.
+	Diff(a, b []string) []DiffLine
+}
+
+// DifferFunc is an adapter to allow the use of ordinary functions as Differs.
+type DifferFunc func(a, b []string) []DiffLine
+
+// Diff calls f(a, b).
+func (f DifferFunc) Diff(a, b []string) []DiffLine {
+	return f(a, b)
+}
+
+// Names of the registered diff algorithms.
+const (
+	// AlgorithmPatience is the name of the patience diff algorithm.
+	AlgorithmPatience = "patience"
+	// AlgorithmMyers is the name of the Myers diff algorithm.
+	AlgorithmMyers = "myers"
+	// AlgorithmHistogram is the name of the histogram diff algorithm.
+	AlgorithmHistogram = "histogram"
+	// AlgorithmLCS is the name of the dynamic programming LCS diff algorithm.
+	AlgorithmLCS = "lcs"
+)
+
+// ErrUnknownAlgorithm is returned when a diff algorithm is not registered.
+var ErrUnknownAlgorithm = errors.New("unknown diff algorithm")
+
+var (
+	algorithmsMu sync.RWMutex
+	algorithms   = map[string]Differ{
+		AlgorithmPatience:  DifferFunc(Diff),
+		AlgorithmMyers:     DifferFunc(MyersDiff),
+		AlgorithmHistogram: DifferFunc(HistogramDiff),
+		AlgorithmLCS:       DifferFunc(LCSDiff),
+	}
+)
+
+// RegisterAlgorithm makes a diff algorithm available by name.
+// Registering an existing name replaces the algorithm.
+func RegisterAlgorithm(name string, d Differ) {
+	if d == nil {
+		panic("patience: RegisterAlgorithm differ is nil")
+	}
+	algorithmsMu.Lock()
+	defer algorithmsMu.Unlock()
+	algorithms[name] = d
+}
+
+// Algorithm returns the diff algorithm registered with the specified name.
+func Algorithm(name string) (Differ, error) {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+	d, ok := algorithms[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, name)
+	}
+	return d, nil
+}
+
+// Algorithms returns the sorted names of the registered diff algorithms.
+func Algorithms() []string {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+	names := make([]string, 0, len(algorithms))
+	for name := range algorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
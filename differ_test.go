@@ -0,0 +1,84 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// randomLines returns n random lines drawn from an alphabet of size k.
+func randomLines(r *rand.Rand, n, k int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = strconv.Itoa(r.Intn(k))
+	}
+	return lines
+}
+
+// diffSides returns the source and destination slices of a diff.
+func diffSides(diffs []DiffLine) ([]string, []string) {
+	a, b := []string{}, []string{}
+	for _, l := range diffs {
+		if l.Type != Insert {
+			a = append(a, l.Text)
+		}
+		if l.Type != Delete {
+			b = append(b, l.Text)
+		}
+	}
+	return a, b
+}
+
+func TestAlgorithm(t *testing.T) {
+	for _, name := range []string{AlgorithmPatience, AlgorithmMyers, AlgorithmHistogram, AlgorithmLCS} {
+		if _, err := Algorithm(name); err != nil {
+			t.Errorf("Algorithm(%q) error = %v", name, err)
+		}
+	}
+	if _, err := Algorithm("unknown"); !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("Algorithm(%q) error = %v, want %v", "unknown", err, ErrUnknownAlgorithm)
+	}
+}
+
+func TestRegisterAlgorithm(t *testing.T) {
+	want := []DiffLine{{Text: "x", Type: Equal}}
+	RegisterAlgorithm("test", DifferFunc(func(a, b []string) []DiffLine {
+		return want
+	}))
+	defer func() {
+		algorithmsMu.Lock()
+		delete(algorithms, "test")
+		algorithmsMu.Unlock()
+	}()
+
+	d, err := Algorithm("test")
+	if err != nil {
+		t.Fatalf("Algorithm() error = %v", err)
+	}
+	if got := d.Diff(nil, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+	if got := Algorithms(); !reflect.DeepEqual(got, []string{"histogram", "lcs", "myers", "patience", "test"}) {
+		t.Errorf("Algorithms() = %v", got)
+	}
+}
+
+// TestAlgorithmsReconstruct tests that every algorithm produces a diff
+// from which both the source and destination can be reconstructed.
+func TestAlgorithmsReconstruct(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, name := range Algorithms() {
+		d, _ := Algorithm(name)
+		for n := 0; n < 200; n++ {
+			a := randomLines(r, r.Intn(40), 1+r.Intn(10))
+			b := randomLines(r, r.Intn(40), 1+r.Intn(10))
+			gotA, gotB := diffSides(d.Diff(a, b))
+			if !reflect.DeepEqual(gotA, a) || !reflect.DeepEqual(gotB, b) {
+				t.Fatalf("%s: Diff(%v, %v) reconstructs %v, %v", name, a, b, gotA, gotB)
+			}
+		}
+	}
+}
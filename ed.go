@@ -0,0 +1,150 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// edCommand matches an ed command, capturing the line range and command letter.
+var edCommand = regexp.MustCompile(`^(?:(\d+)(?:,(\d+))?)?([acd])$`)
+
+// edSubstituteDot is the ed command that removes the leading dot of a line
+// escaped as "..".
+const edSubstituteDot = "s/.//"
+
+// appendEdText appends lines of text to an ed script, terminated by ".".
+// A line consisting of a single dot is written as ".." and unescaped by a
+// substitute command, after which appending resumes.
+func appendEdText(s []string, lines []string) []string {
+	for i, l := range lines {
+		if l != "." {
+			s = append(s, l)
+			continue
+		}
+		s = append(s, "..", ".", edSubstituteDot)
+		if i < len(lines)-1 {
+			s = append(s, "a")
+		}
+	}
+	if len(lines) == 0 || lines[len(lines)-1] != "." {
+		s = append(s, ".")
+	}
+	return s
+}
+
+// EdScriptText returns the diff as an ed script, like diff -e. The commands
+// are in reverse order, so that the line numbers of each command are not
+// affected by the commands before it.
+func EdScriptText(diffs []DiffLine) string {
+	s := []string{}
+	blocks := editBlocks(diffs)
+	for i := len(blocks) - 1; i >= 0; i-- {
+		b := blocks[i]
+		s = append(s, normalRange(b.srcStart, b.srcEnd)+b.command())
+		if len(b.inserted) > 0 {
+			s = appendEdText(s, b.inserted)
+		}
+	}
+	return strings.Join(s, "\n")
+}
+
+// ApplyEdScript returns the source lines with an ed script applied, such as
+// a script of EdScriptText or diff -e. The append, change and delete
+// commands, with optional line ranges, are supported, as is the substitute
+// command that unescapes lines of text consisting of a single dot.
+// A *ParseError is returned if the script is invalid or a line range is
+// out of bounds.
+func ApplyEdScript(src []string, r io.Reader) ([]string, error) {
+	dst := make([]string, len(src))
+	copy(dst, src)
+
+	// next returns the next line of the script, or false at the end.
+	br := bufio.NewReader(r)
+	n := 0
+	next := func() (string, bool, error) {
+		line, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", false, err
+		}
+		if len(line) == 0 && errors.Is(err, io.EOF) {
+			return "", false, nil
+		}
+		n++
+		return strings.TrimSuffix(line, "\n"), true, nil
+	}
+
+	// cur is the 1-based current line, as in ed.
+	cur := len(dst)
+	for {
+		line, ok, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		if line == edSubstituteDot {
+			if cur < 1 || !strings.HasPrefix(dst[cur-1], ".") {
+				return nil, &ParseError{Line: n, Msg: "substitute command without a dot to remove"}
+			}
+			dst[cur-1] = dst[cur-1][1:]
+			continue
+		}
+
+		m := edCommand.FindStringSubmatch(line)
+		if m == nil {
+			return nil, &ParseError{Line: n, Msg: "invalid command " + strconv.Quote(line)}
+		}
+		start, end := cur, cur
+		if len(m[1]) > 0 {
+			start, _ = strconv.Atoi(m[1])
+			end = start
+			if len(m[2]) > 0 {
+				end, _ = strconv.Atoi(m[2])
+			}
+		}
+		cmd := m[3]
+		if end < start || end > len(dst) || (start < 1 && cmd != "a") {
+			return nil, &ParseError{Line: n, Msg: "line range out of bounds"}
+		}
+
+		// Read the lines of text of appends and changes.
+		text := []string{}
+		if cmd != "d" {
+			for {
+				t, ok, err := next()
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					return nil, &ParseError{Line: n, Msg: `text not terminated by "."`}
+				}
+				if t == "." {
+					break
+				}
+				text = append(text, t)
+			}
+		}
+
+		// Replace the lines of the range, or none after the line of an append.
+		lo, hi := start-1, end
+		if cmd == "a" {
+			lo, hi = end, end
+		}
+		dst = append(dst[:lo], append(text, dst[hi:]...)...)
+		switch {
+		case len(text) > 0:
+			cur = lo + len(text)
+		case cmd == "d" && lo < len(dst):
+			cur = lo + 1
+		default:
+			cur = lo
+		}
+	}
+	return dst, nil
+}
@@ -0,0 +1,194 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEdScriptText(t *testing.T) {
+	type args struct {
+		a []string
+		b []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Equal",
+			args: args{
+				a: []string{"a", "b"},
+				b: []string{"a", "b"},
+			},
+			want: "",
+		},
+		{
+			name: "Commands in reverse order",
+			args: args{
+				a: strings.Split("the\nquick\nbrown\nchicken\njumps\nover\nthe\ndog", "\n"),
+				b: strings.Split("the\nquick\nbrown\nfox\njumps\nover\nthe\nlazy\ndog", "\n"),
+			},
+			want: "7a\nlazy\n.\n4c\nfox\n.",
+		},
+		{
+			name: "Delete and add at the start",
+			args: args{
+				a: []string{"a", "b", "c", "d"},
+				b: []string{"x", "a", "d"},
+			},
+			want: "2,3d\n0a\nx\n.",
+		},
+		{
+			name: "Lines of a single dot",
+			args: args{
+				a: []string{"a", "b", "c"},
+				b: []string{"a", ".", "x", ".", "c"},
+			},
+			want: "2c\n..\n.\ns/.//\na\nx\n..\n.\ns/.//",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EdScriptText(Diff(tt.args.a, tt.args.b)); got != tt.want {
+				t.Errorf("EdScriptText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyEdScript(t *testing.T) {
+	type args struct {
+		src    []string
+		script string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     []string
+		wantLine int
+	}{
+		{
+			name: "Empty script",
+			args: args{
+				src:    []string{"a"},
+				script: "",
+			},
+			want: []string{"a"},
+		},
+		{
+			name: "Append, change and delete",
+			args: args{
+				src:    []string{"a", "b", "c", "d"},
+				script: "4a\ne\nf\n.\n2,3c\nx\n.\n1d",
+			},
+			want: []string{"x", "d", "e", "f"},
+		},
+		{
+			name: "Append before the first line",
+			args: args{
+				src:    []string{"a"},
+				script: "0a\nx\n.",
+			},
+			want: []string{"x", "a"},
+		},
+		{
+			name: "Lines of a single dot",
+			args: args{
+				src:    []string{"a", "b"},
+				script: "1a\n..\n.\ns/.//\na\n..\n.\ns/.//",
+			},
+			want: []string{"a", ".", ".", "b"},
+		},
+		{
+			name: "Invalid command",
+			args: args{
+				src:    []string{"a"},
+				script: "1a\nx\n.\n1p",
+			},
+			wantLine: 4,
+		},
+		{
+			name: "Line range out of bounds",
+			args: args{
+				src:    []string{"a"},
+				script: "1,2d",
+			},
+			wantLine: 1,
+		},
+		{
+			name: "Text not terminated",
+			args: args{
+				src:    []string{"a"},
+				script: "1c\nx",
+			},
+			wantLine: 2,
+		},
+		{
+			name: "Substitute without a dot",
+			args: args{
+				src:    []string{"a"},
+				script: "1a\nx\n.\ns/.//",
+			},
+			wantLine: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyEdScript(tt.args.src, strings.NewReader(tt.args.script))
+			if tt.wantLine > 0 {
+				var perr *ParseError
+				if !errors.As(err, &perr) || perr.Line != tt.wantLine {
+					t.Errorf("ApplyEdScript() error = %v, want error at line %d", err, tt.wantLine)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyEdScript() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyEdScript() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestApplyEdScriptLongLines tests that lines longer than the buffer size
+// of a bufio.Scanner are applied.
+func TestApplyEdScriptLongLines(t *testing.T) {
+	a := []string{"a", strings.Repeat("b", 70000), "c"}
+	b := []string{"a", strings.Repeat("x", 70000), "c", strings.Repeat("y", 200000)}
+	got, err := ApplyEdScript(a, strings.NewReader(EdScriptText(Diff(a, b))))
+	if err != nil {
+		t.Fatalf("ApplyEdScript() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("ApplyEdScript() = %d lines, want %d lines", len(got), len(b))
+	}
+}
+
+// TestApplyEdScriptRandom tests that applying the ed script of a diff to
+// the source returns the destination.
+func TestApplyEdScriptRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		a := randomLines(r, r.Intn(20), 5)
+		b := randomLines(r, r.Intn(20), 5)
+		for j := range b {
+			if r.Intn(4) == 0 {
+				b[j] = "."
+			}
+		}
+		got, err := ApplyEdScript(a, strings.NewReader(EdScriptText(Diff(a, b))))
+		if err != nil {
+			t.Fatalf("ApplyEdScript(%q) error = %v", a, err)
+		}
+		if len(got) != len(b) || (len(b) > 0 && !reflect.DeepEqual(got, b)) {
+			t.Fatalf("ApplyEdScript(%q) = %q, want %q", a, got, b)
+		}
+	}
+}
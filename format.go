@@ -3,6 +3,7 @@ package patience
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -20,18 +21,33 @@ func typeSymbol(t DiffType) string {
 	}
 }
 
-// DiffText returns the source and destination texts (all equalities, insertions and deletions).
-func DiffText(diffs []DiffLine) string {
+// DiffTextOptions represents the options for DiffTextWithOptions.
+type DiffTextOptions struct {
+	// Color is the color scheme of the text. If nil, the text is not colored.
+	Color *ColorScheme
+}
+
+// DiffTextWithOptions returns the source and destination texts (all equalities, insertions and deletions).
+func DiffTextWithOptions(diffs []DiffLine, opts DiffTextOptions) string {
 	s := make([]string, len(diffs))
 	for i, l := range diffs {
 		if len(l.Text) == 0 && l.Type == Equal {
 			continue
 		}
-		s[i] = fmt.Sprintf("%s%s", typeSymbol(l.Type), l.Text)
+		if opts.Color != nil {
+			s[i] = opts.Color.line(l)
+		} else {
+			s[i] = fmt.Sprintf("%s%s", typeSymbol(l.Type), l.Text)
+		}
 	}
 	return strings.Join(s, "\n")
 }
 
+// DiffText returns the source and destination texts (all equalities, insertions and deletions).
+func DiffText(diffs []DiffLine) string {
+	return DiffTextWithOptions(diffs, DiffTextOptions{})
+}
+
 // DiffTextA returns the source text (all equalities and deletions).
 func DiffTextA(diffs []DiffLine) string {
 	s := []string{}
@@ -74,26 +90,47 @@ type UnifiedDiffOptions struct {
 	SrcHeader string
 	// DstHeader is the header for the destination file.
 	DstHeader string
+	// Color is the color scheme of the text. If nil, the text is not colored.
+	Color *ColorScheme
+	// IgnoreBlankLines omits hunks whose changed lines are all empty (diff -B).
+	IgnoreBlankLines bool
 }
 
 // UnifiedDiffTextWithOptions returns the diff text in unidiff format.
 func UnifiedDiffTextWithOptions(diffs []DiffLine, opts UnifiedDiffOptions) string {
-	hunks := makeHunks(diffs, opts.Precontext, opts.Postcontext)
+	hunks := HunksWithOptions(diffs, HunkOptions{
+		Precontext:       opts.Precontext,
+		Postcontext:      opts.Postcontext,
+		IgnoreBlankLines: opts.IgnoreBlankLines,
+	})
+	return UnifiedHunksText(hunks, opts)
+}
+
+// UnifiedHunksText returns the text of hunks in unidiff format, such as the
+// rejected hunks of a patch. The context and blank line options are ignored.
+func UnifiedHunksText(hunks []Hunk, opts UnifiedDiffOptions) string {
+	c := opts.Color
+	if c == nil {
+		c = &ColorScheme{}
+	}
 	s := []string{}
 	if len(opts.SrcHeader) > 0 {
-		s = append(s, fmt.Sprintf("--- %s", opts.SrcHeader))
+		s = append(s, colorize(c.Header, fmt.Sprintf("--- %s", opts.SrcHeader)))
 	}
 	if len(opts.DstHeader) > 0 {
-		s = append(s, fmt.Sprintf("+++ %s", opts.DstHeader))
+		s = append(s, colorize(c.Header, fmt.Sprintf("+++ %s", opts.DstHeader)))
 	}
 	for _, h := range hunks {
-		s = append(s, fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.SrcStart, h.SrcLines, h.DstStart, h.DstLines))
+		header := colorize(
+			c.HunkHeader,
+			fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.SrcStart, h.SrcLines, h.DstStart, h.DstLines),
+		)
+		if len(h.Section) > 0 {
+			header += " " + h.Section
+		}
+		s = append(s, header)
 		for _, l := range h.Diffs {
-			if l.Type == Equal && len(l.Text) == 0 {
-				s = append(s, "")
-			} else {
-				s = append(s, fmt.Sprintf("%s%s", typeSymbol(l.Type), l.Text))
-			}
+			s = append(s, c.line(l))
 		}
 	}
 	return strings.Join(s, "\n")
@@ -106,3 +143,120 @@ func UnifiedDiffText(diffs []DiffLine) string {
 		UnifiedDiffOptions{Precontext: 3, Postcontext: 3},
 	)
 }
+
+// ContextDiffOptions represents the options for ContextDiffTextWithOptions.
+type ContextDiffOptions struct {
+	// Precontext is the number of lines of context before each change in a hunk.
+	Precontext int
+	// Postcontext is the number of lines of context after each change in a hunk.
+	Postcontext int
+	// SrcHeader is the header for the source file.
+	SrcHeader string
+	// DstHeader is the header for the destination file.
+	DstHeader string
+	// IgnoreBlankLines omits hunks whose changed lines are all empty (diff -B).
+	IgnoreBlankLines bool
+}
+
+// contextRange returns a line range in context format. A range of one line
+// is its line number, and an empty range is the line before the range.
+func contextRange(start, lines int) string {
+	if lines <= 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, start+lines-1)
+}
+
+// contextSymbols returns the context format symbol of each line of a hunk.
+// Deletions and insertions in the same block of changes are changed lines.
+func contextSymbols(diffs []DiffLine) []string {
+	symbols := make([]string, len(diffs))
+	for i := 0; i < len(diffs); {
+		if diffs[i].Type == Equal {
+			symbols[i] = " "
+			i++
+			continue
+		}
+
+		// Find the end of the block of changes and whether it is mixed.
+		j, dels, inss := i, 0, 0
+		for ; j < len(diffs) && diffs[j].Type != Equal; j++ {
+			if diffs[j].Type == Delete {
+				dels++
+			} else {
+				inss++
+			}
+		}
+		for k := i; k < j; k++ {
+			if dels > 0 && inss > 0 {
+				symbols[k] = "!"
+			} else {
+				symbols[k] = typeSymbol(diffs[k].Type)
+			}
+		}
+		i = j
+	}
+	return symbols
+}
+
+// appendContextLines appends the lines of a hunk that are not of the
+// excluded diff type in context format.
+func appendContextLines(s []string, diffs []DiffLine, symbols []string, exclude DiffType) []string {
+	for i, l := range diffs {
+		switch {
+		case l.Type == exclude:
+			continue
+		case l.Type == Equal && len(l.Text) == 0:
+			s = append(s, "")
+		default:
+			s = append(s, fmt.Sprintf("%s %s", symbols[i], l.Text))
+		}
+	}
+	return s
+}
+
+// ContextDiffTextWithOptions returns the diff text in context format.
+func ContextDiffTextWithOptions(diffs []DiffLine, opts ContextDiffOptions) string {
+	hunks := HunksWithOptions(diffs, HunkOptions{
+		Precontext:       opts.Precontext,
+		Postcontext:      opts.Postcontext,
+		IgnoreBlankLines: opts.IgnoreBlankLines,
+	})
+	return ContextHunksText(hunks, opts)
+}
+
+// ContextHunksText returns the text of hunks in context format. The old
+// lines of a hunk are omitted if it has no deletions, and the new lines
+// are omitted if it has no insertions. The context and blank line options
+// are ignored.
+func ContextHunksText(hunks []Hunk, opts ContextDiffOptions) string {
+	s := []string{}
+	if len(opts.SrcHeader) > 0 {
+		s = append(s, fmt.Sprintf("*** %s", opts.SrcHeader))
+	}
+	if len(opts.DstHeader) > 0 {
+		s = append(s, fmt.Sprintf("--- %s", opts.DstHeader))
+	}
+	for _, h := range hunks {
+		symbols := contextSymbols(h.Diffs)
+		stats := Stats(h.Diffs)
+		s = append(s, "***************")
+		s = append(s, fmt.Sprintf("*** %s ****", contextRange(h.SrcStart, h.SrcLines)))
+		if stats.Deletions > 0 {
+			s = appendContextLines(s, h.Diffs, symbols, Insert)
+		}
+		s = append(s, fmt.Sprintf("--- %s ----", contextRange(h.DstStart, h.DstLines)))
+		if stats.Insertions > 0 {
+			s = appendContextLines(s, h.Diffs, symbols, Delete)
+		}
+	}
+	return strings.Join(s, "\n")
+}
+
+// ContextDiffText returns the diff text in context format with a context of 3 lines.
+func ContextDiffText(diffs []DiffLine) string {
+	return ContextDiffTextWithOptions(
+		diffs,
+		ContextDiffOptions{Precontext: 3, Postcontext: 3},
+	)
+}
@@ -206,6 +206,21 @@ func TestUnifiedDiffTextWithOptions(t *testing.T) {
 			},
 			want: "--- a.txt\n+++ b.txt\n@@ -2,2 +2,3 @@\n b\n+c\n",
 		},
+		{
+			name: "Test ignore blank lines",
+			args: args{
+				diffs: Diff(
+					[]string{"a", "b", "c", "d", "e", "f"},
+					[]string{"a", "", "b", "c", "d", "x", "f"},
+				),
+				opts: UnifiedDiffOptions{
+					Precontext:       1,
+					Postcontext:      1,
+					IgnoreBlankLines: true,
+				},
+			},
+			want: "@@ -4,3 +5,3 @@\n d\n-e\n+x\n f",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -215,3 +230,137 @@ func TestUnifiedDiffTextWithOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestUnifiedHunksText(t *testing.T) {
+	hunks := []Hunk{
+		{
+			Diffs: []DiffLine{
+				{Type: Equal, Text: "a"},
+				{Type: Delete, Text: "b"},
+			},
+			SrcStart: 3,
+			SrcLines: 2,
+			DstStart: 4,
+			DstLines: 1,
+			Section:  "func f() {",
+		},
+	}
+	want := "--- a.txt\n+++ b.txt\n@@ -3,2 +4,1 @@ func f() {\n a\n-b"
+	if got := UnifiedHunksText(hunks, UnifiedDiffOptions{SrcHeader: "a.txt", DstHeader: "b.txt"}); got != want {
+		t.Errorf("UnifiedHunksText() = %v, want %v", got, want)
+	}
+}
+
+func TestContextDiffTextWithOptions(t *testing.T) {
+	type args struct {
+		diffs []DiffLine
+		opts  ContextDiffOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Test multiple hunks with context",
+			args: args{
+				diffs: []DiffLine{
+					{Type: Equal, Text: "a"},
+					{Type: Equal, Text: "b"},
+					{Type: Insert, Text: "c"},
+					{Type: Equal, Text: "d"},
+					{Type: Equal, Text: "e"},
+					{Type: Equal, Text: "f"},
+					{Type: Delete, Text: "g"},
+					{Type: Insert, Text: "h"},
+					{Type: Equal, Text: "i"},
+					{Type: Delete, Text: "j"},
+					{Type: Equal, Text: "k"},
+					{Type: Equal, Text: "l"},
+				},
+				opts: ContextDiffOptions{
+					Precontext:  1,
+					Postcontext: 1,
+				},
+			},
+			want: "***************\n*** 2,3 ****\n--- 2,4 ----\n  b\n+ c\n  d\n" +
+				"***************\n*** 5,9 ****\n  f\n! g\n  i\n- j\n  k\n--- 6,9 ----\n  f\n! h\n  i\n  k",
+		},
+		{
+			name: "Test empty ranges",
+			args: args{
+				diffs: []DiffLine{
+					{Type: Delete, Text: "a"},
+					{Type: Equal, Text: "b"},
+					{Type: Equal, Text: "c"},
+					{Type: Equal, Text: "d"},
+					{Type: Insert, Text: "e"},
+				},
+				opts: ContextDiffOptions{},
+			},
+			want: "***************\n*** 1 ****\n- a\n--- 0 ----\n" +
+				"***************\n*** 4 ****\n--- 4 ----\n+ e",
+		},
+		{
+			name: "Test source and destination file headers",
+			args: args{
+				diffs: []DiffLine{
+					{Type: Equal, Text: "a"},
+					{Type: Equal, Text: "b"},
+					{Type: Insert, Text: "c"},
+					{Type: Equal, Text: ""},
+				},
+				opts: ContextDiffOptions{
+					Precontext:  1,
+					Postcontext: 1,
+					SrcHeader:   "a.txt",
+					DstHeader:   "b.txt",
+				},
+			},
+			want: "*** a.txt\n--- b.txt\n***************\n*** 2,3 ****\n--- 2,4 ----\n  b\n+ c\n",
+		},
+		{
+			name: "Test ignore blank lines",
+			args: args{
+				diffs: Diff(
+					[]string{"a", "b", "c", "d", "e", "f"},
+					[]string{"a", "", "b", "c", "d", "x", "f"},
+				),
+				opts: ContextDiffOptions{
+					Precontext:       1,
+					Postcontext:      1,
+					IgnoreBlankLines: true,
+				},
+			},
+			want: "***************\n*** 4,6 ****\n  d\n! e\n  f\n--- 5,7 ----\n  d\n! x\n  f",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContextDiffTextWithOptions(tt.args.diffs, tt.args.opts); got != tt.want {
+				t.Errorf("ContextDiffTextWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContextHunksText(t *testing.T) {
+	hunks := []Hunk{
+		{
+			Diffs: []DiffLine{
+				{Type: Equal, Text: "a"},
+				{Type: Delete, Text: "b"},
+				{Type: Insert, Text: "c"},
+				{Type: Insert, Text: "d"},
+			},
+			SrcStart: 3,
+			SrcLines: 2,
+			DstStart: 4,
+			DstLines: 3,
+		},
+	}
+	want := "*** a.txt\n--- b.txt\n***************\n*** 3,4 ****\n  a\n! b\n--- 4,6 ----\n  a\n! c\n! d"
+	if got := ContextHunksText(hunks, ContextDiffOptions{SrcHeader: "a.txt", DstHeader: "b.txt"}); got != want {
+		t.Errorf("ContextHunksText() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import "context"
+
+// maxChainLength is the maximum number of occurrences of an element in
+// the source slice for it to be considered as a histogram diff anchor.
+const maxChainLength = 64
+
+// histogram holds the occurrences of the element IDs of a source region.
+// It is reused for every region of a histogram diff.
+type histogram struct {
+	// counts is the number of occurrences of each ID in the region.
+	counts []int
+	// first is the index of the first occurrence of each ID, or -1.
+	first []int
+	// next is the index of the next occurrence of the element at each
+	// index, or -1.
+	next []int
+}
+
+// newHistogram returns a histogram for element IDs less than n, and
+// regions of up to size elements.
+func newHistogram(n, size int) *histogram {
+	h := &histogram{
+		counts: make([]int, n),
+		first:  make([]int, n),
+		next:   make([]int, size),
+	}
+	for i := range h.first {
+		h.first[i] = -1
+	}
+	return h
+}
+
+// anchor returns the start indices and length of the common region of
+// slices a and b that contains the lowest occurring element of a.
+// Ties are broken by choosing the longest region. A length of zero is
+// returned if there is no suitable region.
+func (h *histogram) anchor(a, b []int) (int, int, int) {
+	for i := len(a) - 1; i >= 0; i-- {
+		e := a[i]
+		h.next[i] = h.first[e]
+		h.first[e] = i
+		h.counts[e]++
+	}
+
+	ai, bi, n := 0, 0, 0
+	lowest := maxChainLength + 1
+	for j := 0; j < len(b); {
+		next := j + 1
+		if h.counts[b[j]] > maxChainLength {
+			j = next
+			continue
+		}
+		for i := h.first[b[j]]; i >= 0; i = h.next[i] {
+			// Extend the region around the matching elements.
+			as, bs := i, j
+			for as > 0 && bs > 0 && a[as-1] == b[bs-1] {
+				as--
+				bs--
+			}
+			ae, be := i+1, j+1
+			for ae < len(a) && be < len(b) && a[ae] == b[be] {
+				ae++
+				be++
+			}
+
+			// Find the lowest occurrence count of the elements in the region.
+			count := h.counts[b[j]]
+			for _, e := range a[as:ae] {
+				count = min(count, h.counts[e])
+			}
+
+			if count < lowest || (count == lowest && ae-as > n) {
+				ai, bi, n = as, bs, ae-as
+				lowest = count
+			}
+			next = max(next, be)
+		}
+		j = next
+	}
+
+	for _, e := range a {
+		h.first[e] = -1
+		h.counts[e] = 0
+	}
+	return ai, bi, n
+}
+
+// histogramDiff returns the histogram diff of two slices of elements,
+// comparing the element IDs of each slice. The IDs must be less than n.
+func histogramDiff[T any](a, b []T, ia, ib []int, n int) []Edit[T] {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	// Diff regions from an explicit stack, appending to a single buffer.
+	// Regions are pushed in reverse order so that they are popped in order.
+	h := newHistogram(n, len(a))
+	diffs := make([]Edit[T], 0, len(a)+len(b))
+	stack := []region{{aHi: len(a), bHi: len(b)}}
+	for len(stack) > 0 {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if r.equal {
+			diffs = appendEdits(diffs, a[r.aLo:r.aHi], Equal)
+			continue
+		}
+
+		// Find equal elements at the head of the region.
+		head := r.aLo
+		for r.aLo < r.aHi && r.bLo < r.bHi && ia[r.aLo] == ib[r.bLo] {
+			r.aLo++
+			r.bLo++
+		}
+		diffs = appendEdits(diffs, a[head:r.aLo], Equal)
+
+		// Find equal elements at the tail of the region.
+		tail := r.aHi
+		for r.aLo < r.aHi && r.bLo < r.bHi && ia[r.aHi-1] == ib[r.bHi-1] {
+			r.aHi--
+			r.bHi--
+		}
+		if r.aHi < tail {
+			stack = append(stack, region{aLo: r.aHi, aHi: tail, equal: true})
+		}
+
+		switch {
+		case r.aLo == r.aHi:
+			diffs = appendEdits(diffs, b[r.bLo:r.bHi], Insert)
+			continue
+		case r.bLo == r.bHi:
+			diffs = appendEdits(diffs, a[r.aLo:r.aHi], Delete)
+			continue
+		}
+
+		// Find the common region containing the lowest occurring element.
+		ai, bi, n := h.anchor(ia[r.aLo:r.aHi], ib[r.bLo:r.bHi])
+
+		// If there is no region, fall back to a minimal diff of all elements.
+		if n == 0 {
+			diffs, _ = myersDiff(
+				context.Background(),
+				diffs,
+				a[r.aLo:r.aHi], b[r.bLo:r.bHi],
+				ia[r.aLo:r.aHi], ib[r.bLo:r.bHi],
+			)
+			continue
+		}
+
+		// Push the regions after and before the common region, and the
+		// common region itself.
+		ai, bi = r.aLo+ai, r.bLo+bi
+		stack = append(
+			stack,
+			region{aLo: ai + n, aHi: r.aHi, bLo: bi + n, bHi: r.bHi},
+			region{aLo: ai, aHi: ai + n, equal: true},
+			region{aLo: r.aLo, aHi: ai, bLo: r.bLo, bHi: bi},
+		)
+	}
+
+	return diffs
+}
+
+// HistogramDiff returns the histogram diff of two slices of strings.
+// Histogram diff is an extension of patience diff that anchors on the
+// lowest occurring common elements, rather than only unique ones.
+func HistogramDiff(a, b []string) []DiffLine {
+	ia, ib, n := intern(a, b)
+	return toDiffLines(histogramDiff(a, b, ia, ib, n))
+}
@@ -0,0 +1,145 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_histogram_anchor(t *testing.T) {
+	type args struct {
+		a []string
+		b []string
+	}
+	tests := []struct {
+		name   string
+		args   args
+		wantAi int
+		wantBi int
+		wantN  int
+	}{
+		{
+			name: "Test no common elements",
+			args: args{
+				a: []string{"a", "b"},
+				b: []string{"c", "d"},
+			},
+			wantAi: 0,
+			wantBi: 0,
+			wantN:  0,
+		},
+		{
+			name: "Test region containing the lowest occurring element",
+			args: args{
+				a: []string{"x", "a", "x", "b", "x"},
+				b: []string{"y", "x", "b", "x", "a"},
+			},
+			wantAi: 2,
+			wantBi: 1,
+			wantN:  3,
+		},
+		{
+			name: "Test longest region when occurrences are equal",
+			args: args{
+				a: []string{"a", "b", "c", "d", "e"},
+				b: []string{"d", "e", "z", "a", "b", "c"},
+			},
+			wantAi: 0,
+			wantBi: 3,
+			wantN:  3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ia, ib, n := intern(tt.args.a, tt.args.b)
+			h := newHistogram(n, len(ia))
+			// Find the anchor twice to test that the histogram is reset.
+			for k := 0; k < 2; k++ {
+				gotAi, gotBi, gotN := h.anchor(ia, ib)
+				if gotAi != tt.wantAi || gotBi != tt.wantBi || gotN != tt.wantN {
+					t.Errorf(
+						"anchor() = %v, %v, %v, want %v, %v, %v",
+						gotAi, gotBi, gotN, tt.wantAi, tt.wantBi, tt.wantN,
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestHistogramDiff(t *testing.T) {
+	type args struct {
+		a []string
+		b []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []DiffLine
+	}{
+		{
+			name: "Test empty slices",
+			args: args{
+				a: []string{},
+				b: []string{},
+			},
+			want: nil,
+		},
+		{
+			name: "Test empty slice a",
+			args: args{
+				a: []string{},
+				b: []string{"a"},
+			},
+			want: []DiffLine{
+				{Text: "a", Type: Insert, DstLine: 1},
+			},
+		},
+		{
+			name: "Test empty slice b",
+			args: args{
+				a: []string{"a"},
+				b: []string{},
+			},
+			want: []DiffLine{
+				{Text: "a", Type: Delete, SrcLine: 1},
+			},
+		},
+		{
+			name: "Test equal elements at the head and tail of slices a and b",
+			args: args{
+				a: []string{"a", "b", "c"},
+				b: []string{"a", "d", "c"},
+			},
+			want: []DiffLine{
+				{Text: "a", Type: Equal, SrcLine: 1, DstLine: 1},
+				{Text: "b", Type: Delete, SrcLine: 2},
+				{Text: "d", Type: Insert, DstLine: 2},
+				{Text: "c", Type: Equal, SrcLine: 3, DstLine: 3},
+			},
+		},
+		{
+			name: "Test anchoring on non-unique elements",
+			args: args{
+				a: []string{"a", "x", "b", "x", "c"},
+				b: []string{"x", "b", "y", "c", "x"},
+			},
+			want: []DiffLine{
+				{Text: "a", Type: Delete, SrcLine: 1},
+				{Text: "x", Type: Equal, SrcLine: 2, DstLine: 1},
+				{Text: "b", Type: Equal, SrcLine: 3, DstLine: 2},
+				{Text: "x", Type: Delete, SrcLine: 4},
+				{Text: "y", Type: Insert, DstLine: 3},
+				{Text: "c", Type: Equal, SrcLine: 5, DstLine: 4},
+				{Text: "x", Type: Insert, DstLine: 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HistogramDiff(tt.args.a, tt.args.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HistogramDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"html/template"
+	"strings"
+)
+
+// DefaultHTMLTemplate is the text of the default template of HTML reports.
+// It is executed with an *HTMLData.
+const DefaultHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+table { border-collapse: collapse; table-layout: fixed; width: 100%; font-family: monospace; font-size: 13px; }
+td { padding: 0 0.5em; vertical-align: top; white-space: pre-wrap; word-break: break-all; }
+td.num { width: 4em; color: #6e7781; text-align: right; user-select: none; }
+td.sign { width: 1em; user-select: none; }
+th { font-family: sans-serif; text-align: left; padding: 0.25em 0.5em; background: #f6f8fa; }
+tr.equal td, td.equal { background: #ffffff; }
+tr.insert td, td.insert { background: #e6ffec; }
+tr.delete td, td.delete { background: #ffebe9; }
+td.empty { background: #f6f8fa; }
+span.changed { font-weight: bold; }
+tr.insert span.changed, td.insert span.changed { background: #abf2bc; }
+tr.delete span.changed, td.delete span.changed { background: #ffc0c0; }
+details.unchanged > summary, div.unchanged { padding: 0.25em 0.5em; background: #ddf4ff; color: #57606a; font-family: sans-serif; font-size: 13px; cursor: pointer; }
+</style>
+</head>
+<body>
+{{- if .Title}}
+<h1>{{.Title}}</h1>
+{{- end}}
+{{- if or .SrcHeader .DstHeader}}
+<table>
+{{- if .Split}}
+<tr><th>{{.SrcHeader}}</th><th>{{.DstHeader}}</th></tr>
+{{- else}}
+<tr><th>--- {{.SrcHeader}}<br>+++ {{.DstHeader}}</th></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- range .Sections}}
+{{- if .Collapsed}}
+{{- if .Rows}}
+<details class="unchanged">
+<summary>{{.Unchanged}} unchanged line{{if ne .Unchanged 1}}s{{end}}</summary>
+{{template "rows" dict $.Split .Rows}}
+</details>
+{{- else}}
+<div class="unchanged">{{.Unchanged}} unchanged line{{if ne .Unchanged 1}}s{{end}}</div>
+{{- end}}
+{{- else}}
+{{template "rows" dict $.Split .Rows}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+{{define "rows"}}<table>
+{{- if .Split}}
+<colgroup><col style="width: 4em"><col><col style="width: 4em"><col></colgroup>
+{{- range .Rows}}
+<tr>
+{{- range .Lines}}<td class="num">{{if .Number}}{{.Number}}{{end}}</td><td class="{{.Class}}">{{.HTML}}</td>{{end -}}
+</tr>
+{{- end}}
+{{- else}}
+<colgroup><col style="width: 4em"><col style="width: 4em"><col style="width: 1em"><col></colgroup>
+{{- range .Rows}}
+{{- with index .Lines 0}}
+<tr class="{{.Class}}"><td class="num">{{if .SrcLine}}{{.SrcLine}}{{end}}</td><td class="num">{{if .DstLine}}{{.DstLine}}{{end}}</td><td class="sign">{{.Sign}}</td><td>{{.HTML}}</td></tr>
+{{- end}}
+{{- end}}
+{{- end}}
+</table>{{end}}
+`
+
+// defaultHTMLTemplate is the parsed default template of HTML reports.
+var defaultHTMLTemplate = template.Must(NewHTMLTemplate(DefaultHTMLTemplate))
+
+// HTMLView defines the layout of an HTML report.
+type HTMLView int
+
+const (
+	// HTMLViewInline shows the source and destination lines in a single column.
+	HTMLViewInline HTMLView = iota
+	// HTMLViewSplit shows the source and destination lines in two columns.
+	HTMLViewSplit
+)
+
+// HTMLOptions represents the options for HTMLTextWithOptions and HTMLHunksText.
+type HTMLOptions struct {
+	// View is the layout of the report.
+	View HTMLView
+	// Title is the title of the report.
+	Title string
+	// SrcHeader is the header for the source file.
+	SrcHeader string
+	// DstHeader is the header for the destination file.
+	DstHeader string
+	// CollapseUnchanged collapses runs of unchanged lines, leaving Context
+	// lines of context visible around each change.
+	CollapseUnchanged bool
+	// Context is the number of unchanged lines visible around each change.
+	// Negative context is treated as no context.
+	Context int
+	// Template is the template of the report, executed with an *HTMLData.
+	// If nil, the default template is used.
+	Template *template.Template
+}
+
+// HTMLLine represents a line of an HTML report. In the split view, the
+// missing side of a row of changes is an empty line.
+type HTMLLine struct {
+	// SrcLine is the 1-based line number in the source, or 0 if none.
+	SrcLine int
+	// DstLine is the 1-based line number in the destination, or 0 if none.
+	DstLine int
+	// Number is the line number of the side of the line in the split view,
+	// or 0 if none.
+	Number int
+	// Sign is the symbol of the type of the line.
+	Sign string
+	// Class is the CSS class of the line: "equal", "insert", "delete" or "empty".
+	Class string
+	// HTML is the escaped text of the line, with changed segments wrapped
+	// in <span class="changed"> elements.
+	HTML template.HTML
+}
+
+// HTMLRow represents a row of an HTML report. It has one line in the
+// inline view, and the source and destination lines in the split view.
+type HTMLRow struct {
+	Lines []HTMLLine
+}
+
+// HTMLSection represents a run of rows of an HTML report.
+type HTMLSection struct {
+	// Collapsed is true if the section is a run of unchanged lines.
+	Collapsed bool
+	// Unchanged is the number of unchanged lines of a collapsed section.
+	Unchanged int
+	// Rows are the rows of the section. The rows of a collapsed section
+	// between hunks are unknown, so it has none.
+	Rows []HTMLRow
+}
+
+// HTMLData represents the data an HTML report template is executed with.
+type HTMLData struct {
+	Title     string
+	SrcHeader string
+	DstHeader string
+	Split     bool
+	Sections  []HTMLSection
+}
+
+// NewHTMLTemplate parses the text of an HTML report template. Templates
+// may use the "dict" function to pass the Split flag and rows to nested
+// templates, as the default template does.
+func NewHTMLTemplate(text string) (*template.Template, error) {
+	return template.New("patience").Funcs(template.FuncMap{
+		"dict": func(split bool, rows []HTMLRow) map[string]any {
+			return map[string]any{"Split": split, "Rows": rows}
+		},
+	}).Parse(text)
+}
+
+// lineHTML returns the escaped text of a diff line, with changed segments
+// wrapped in span elements.
+func lineHTML(l DiffLine) template.HTML {
+	if len(l.Segments) == 0 {
+		return template.HTML(template.HTMLEscapeString(l.Text)) // nolint:gosec
+	}
+	var sb strings.Builder
+	for _, s := range l.Segments {
+		if s.Type == Equal {
+			sb.WriteString(template.HTMLEscapeString(s.Text))
+		} else {
+			sb.WriteString(`<span class="changed">`)
+			sb.WriteString(template.HTMLEscapeString(s.Text))
+			sb.WriteString(`</span>`)
+		}
+	}
+	return template.HTML(sb.String()) // nolint:gosec
+}
+
+// htmlLine returns the HTML line of a diff line, numbered by the line
+// number of a side in the split view.
+func htmlLine(l *DiffLine, number int) HTMLLine {
+	if l == nil {
+		return HTMLLine{Class: "empty"}
+	}
+	return HTMLLine{
+		SrcLine: l.SrcLine,
+		DstLine: l.DstLine,
+		Number:  number,
+		Sign:    strings.TrimSpace(typeSymbol(l.Type)),
+		Class:   l.Type.String(),
+		HTML:    lineHTML(*l),
+	}
+}
+
+// htmlRows returns the rows of a diff in a view.
+func htmlRows(diffs []DiffLine, view HTMLView) []HTMLRow {
+	rows := []HTMLRow{}
+	if view != HTMLViewSplit {
+		for i := range diffs {
+			rows = append(rows, HTMLRow{Lines: []HTMLLine{htmlLine(&diffs[i], 0)}})
+		}
+		return rows
+	}
+	for _, r := range sideBySideRows(diffs, SideBySideOptions{}) {
+		src, dst := htmlLine(nil, 0), htmlLine(nil, 0)
+		if r.src != nil {
+			src = htmlLine(r.src, r.src.SrcLine)
+		}
+		if r.dst != nil {
+			dst = htmlLine(r.dst, r.dst.DstLine)
+		}
+		rows = append(rows, HTMLRow{Lines: []HTMLLine{src, dst}})
+	}
+	return rows
+}
+
+// htmlSections returns the sections of a diff. If CollapseUnchanged is set,
+// runs of unchanged lines further than Context lines from a change are
+// collapsed.
+func htmlSections(diffs []DiffLine, opts HTMLOptions) []HTMLSection {
+	if !opts.CollapseUnchanged {
+		return []HTMLSection{{Rows: htmlRows(diffs, opts.View)}}
+	}
+
+	// Find the lines within the context of a change.
+	context := max(opts.Context, 0)
+	visible := make([]bool, len(diffs))
+	last := -1
+	for i, l := range diffs {
+		if l.Type != Equal {
+			last = i
+		}
+		visible[i] = last >= 0 && i-last <= context
+	}
+	last = -1
+	for i := len(diffs) - 1; i >= 0; i-- {
+		if diffs[i].Type != Equal {
+			last = i
+		}
+		visible[i] = visible[i] || (last >= 0 && last-i <= context)
+	}
+
+	sections := []HTMLSection{}
+	for i := 0; i < len(diffs); {
+		j := i
+		for j < len(diffs) && visible[j] == visible[i] {
+			j++
+		}
+		s := HTMLSection{Collapsed: !visible[i], Rows: htmlRows(diffs[i:j], opts.View)}
+		if s.Collapsed {
+			s.Unchanged = j - i
+		}
+		sections = append(sections, s)
+		i = j
+	}
+	return sections
+}
+
+// executeHTML returns the text of an HTML report of sections.
+func executeHTML(sections []HTMLSection, opts HTMLOptions) (string, error) {
+	t := opts.Template
+	if t == nil {
+		t = defaultHTMLTemplate
+	}
+	var sb strings.Builder
+	err := t.Execute(&sb, &HTMLData{
+		Title:     opts.Title,
+		SrcHeader: opts.SrcHeader,
+		DstHeader: opts.DstHeader,
+		Split:     opts.View == HTMLViewSplit,
+		Sections:  sections,
+	})
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// HTMLTextWithOptions returns a self-contained HTML document of a diff.
+// An error is returned if the template fails to execute.
+func HTMLTextWithOptions(diffs []DiffLine, opts HTMLOptions) (string, error) {
+	return executeHTML(htmlSections(diffs, opts), opts)
+}
+
+// HTMLHunksText returns a self-contained HTML document of hunks, such as
+// the hunks of a parsed patch. The unchanged lines between hunks are shown
+// as collapsed sections without rows, and the context options are ignored.
+// An error is returned if the template fails to execute.
+func HTMLHunksText(hunks []Hunk, opts HTMLOptions) (string, error) {
+	sections := []HTMLSection{}
+	next := 1
+	for _, h := range hunks {
+		// The start of an empty range is the line before the range.
+		start := h.SrcStart
+		if h.SrcLines == 0 {
+			start++
+		}
+		if n := start - next; n > 0 {
+			sections = append(sections, HTMLSection{Collapsed: true, Unchanged: n})
+		}
+		sections = append(sections, HTMLSection{Rows: htmlRows(h.Diffs, opts.View)})
+		next = start + h.SrcLines
+	}
+	return executeHTML(sections, opts)
+}
+
+// HTMLText returns a self-contained HTML document of a diff in the inline
+// view, with unchanged lines more than 3 lines from a change collapsed.
+func HTMLText(diffs []DiffLine) (string, error) {
+	return HTMLTextWithOptions(diffs, HTMLOptions{CollapseUnchanged: true, Context: 3})
+}
@@ -0,0 +1,151 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+)
+
+// testHTMLTemplate is a compact template of the data of an HTML report.
+var testHTMLTemplate = template.Must(NewHTMLTemplate(
+	`{{range .Sections}}{{if .Collapsed}}[{{.Unchanged}}]{{end}}` +
+		`{{range .Rows}}{{range .Lines}}{{.Class}} {{.SrcLine}} {{.DstLine}} {{.Number}} {{.HTML}};{{end}}{{end}}|{{end}}`,
+))
+
+func TestHTMLTextWithOptions(t *testing.T) {
+	a := strings.Split("1\n2\n3\n<a>\n5\n6", "\n")
+	b := strings.Split("1\n2\n3\n<b>&\n5\n6\n7", "\n")
+
+	type args struct {
+		diffs []DiffLine
+		opts  HTMLOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Inline view",
+			args: args{
+				diffs: Diff(a, b),
+				opts:  HTMLOptions{Template: testHTMLTemplate},
+			},
+			want: "equal 1 1 0 1;equal 2 2 0 2;equal 3 3 0 3;" +
+				"delete 4 0 0 &lt;a&gt;;insert 0 4 0 &lt;b&gt;&amp;;" +
+				"equal 5 5 0 5;equal 6 6 0 6;insert 0 7 0 7;|",
+		},
+		{
+			name: "Split view",
+			args: args{
+				diffs: Diff(a, b)[2:],
+				opts:  HTMLOptions{View: HTMLViewSplit, Template: testHTMLTemplate},
+			},
+			want: "equal 3 3 3 3;equal 3 3 3 3;" +
+				"delete 4 0 4 &lt;a&gt;;insert 0 4 4 &lt;b&gt;&amp;;" +
+				"equal 5 5 5 5;equal 5 5 5 5;" +
+				"equal 6 6 6 6;equal 6 6 6 6;" +
+				"empty 0 0 0 ;insert 0 7 7 7;|",
+		},
+		{
+			name: "Changed segments",
+			args: args{
+				diffs: WordDiff(Diff([]string{"a b"}, []string{"a <c>"})),
+				opts:  HTMLOptions{Template: testHTMLTemplate},
+			},
+			want: `delete 1 0 0 a <span class="changed">b</span>;` +
+				`insert 0 1 0 a <span class="changed">&lt;c&gt;</span>;|`,
+		},
+		{
+			name: "Collapsed unchanged lines",
+			args: args{
+				diffs: Diff(a, b),
+				opts:  HTMLOptions{CollapseUnchanged: true, Context: 1, Template: testHTMLTemplate},
+			},
+			want: "[2]equal 1 1 0 1;equal 2 2 0 2;|" +
+				"equal 3 3 0 3;delete 4 0 0 &lt;a&gt;;insert 0 4 0 &lt;b&gt;&amp;;" +
+				"equal 5 5 0 5;equal 6 6 0 6;insert 0 7 0 7;|",
+		},
+		{
+			name: "Collapsed unchanged lines without context",
+			args: args{
+				diffs: Diff(a, b),
+				opts:  HTMLOptions{CollapseUnchanged: true, Template: testHTMLTemplate},
+			},
+			want: "[3]equal 1 1 0 1;equal 2 2 0 2;equal 3 3 0 3;|" +
+				"delete 4 0 0 &lt;a&gt;;insert 0 4 0 &lt;b&gt;&amp;;|" +
+				"[2]equal 5 5 0 5;equal 6 6 0 6;|" +
+				"insert 0 7 0 7;|",
+		},
+		{
+			name: "Collapsed unchanged lines with negative context",
+			args: args{
+				diffs: Diff(a, b),
+				opts:  HTMLOptions{CollapseUnchanged: true, Context: -1, Template: testHTMLTemplate},
+			},
+			want: "[3]equal 1 1 0 1;equal 2 2 0 2;equal 3 3 0 3;|" +
+				"delete 4 0 0 &lt;a&gt;;insert 0 4 0 &lt;b&gt;&amp;;|" +
+				"[2]equal 5 5 0 5;equal 6 6 0 6;|" +
+				"insert 0 7 0 7;|",
+		},
+		{
+			name: "Collapsed unchanged lines without changes",
+			args: args{
+				diffs: Diff(a, a),
+				opts:  HTMLOptions{CollapseUnchanged: true, Context: 3, Template: testHTMLTemplate},
+			},
+			want: "[6]equal 1 1 0 1;equal 2 2 0 2;equal 3 3 0 3;equal 4 4 0 &lt;a&gt;;equal 5 5 0 5;equal 6 6 0 6;|",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HTMLTextWithOptions(tt.args.diffs, tt.args.opts)
+			if err != nil {
+				t.Fatalf("HTMLTextWithOptions() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HTMLTextWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLHunksText(t *testing.T) {
+	a := strings.Split("1\n2\n3\n4\n5\n6\n7\n8", "\n")
+	b := strings.Split("1\n2\n3\nx\n5\n6\n7\n8\ny", "\n")
+
+	got, err := HTMLHunksText(Hunks(Diff(a, b), 1, 1), HTMLOptions{Template: testHTMLTemplate})
+	if err != nil {
+		t.Fatalf("HTMLHunksText() error = %v", err)
+	}
+	want := "[2]|equal 3 3 0 3;delete 4 0 0 4;insert 0 4 0 x;equal 5 5 0 5;|" +
+		"[2]|equal 8 8 0 8;insert 0 9 0 y;|"
+	if got != want {
+		t.Errorf("HTMLHunksText() = %q, want %q", got, want)
+	}
+}
+
+func TestHTMLText(t *testing.T) {
+	a := strings.Split("1\n2\n3\n4\n5\n<a>", "\n")
+	b := strings.Split("1\n2\n3\n4\n5\n<b>", "\n")
+
+	got, err := HTMLText(Diff(a, b))
+	if err != nil {
+		t.Fatalf("HTMLText() error = %v", err)
+	}
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`<details class="unchanged">`,
+		"<summary>2 unchanged lines</summary>",
+		`<tr class="delete"><td class="num">6</td><td class="num"></td><td class="sign">-</td><td>&lt;a&gt;</td></tr>`,
+		`<tr class="insert"><td class="num"></td><td class="num">6</td>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTMLText() = %v, want it to contain %v", got, want)
+		}
+	}
+	if strings.Contains(got, "<a>") {
+		t.Errorf("HTMLText() = %v, want escaped text", got)
+	}
+}
@@ -0,0 +1,82 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+// invertLine returns a diff line with its insertion and deletion swapped.
+func invertLine(l DiffLine) DiffLine {
+	// Delete and Insert are negations of each other, and Equal is zero.
+	l.Type = -l.Type
+	l.SrcLine, l.DstLine = l.DstLine, l.SrcLine
+	if l.Segments != nil {
+		segments := make([]Segment, len(l.Segments))
+		for i, s := range l.Segments {
+			segments[i] = Segment{Text: s.Text, Type: -s.Type}
+		}
+		l.Segments = segments
+	}
+	return l
+}
+
+// Invert returns the inverse of a diff, from the destination to the source.
+// Insertions and deletions are swapped, as are source and destination line
+// numbers. Within each block of changes, deletions are placed before
+// insertions.
+func Invert(diffs []DiffLine) []DiffLine {
+	if diffs == nil {
+		return nil
+	}
+	out := make([]DiffLine, 0, len(diffs))
+	for i := 0; i < len(diffs); {
+		if diffs[i].Type == Equal {
+			out = append(out, invertLine(diffs[i]))
+			i++
+			continue
+		}
+
+		// Find the end of the block of changes.
+		j := i
+		for j < len(diffs) && diffs[j].Type != Equal {
+			j++
+		}
+		for _, t := range []DiffType{Insert, Delete} {
+			for _, l := range diffs[i:j] {
+				if l.Type == t {
+					out = append(out, invertLine(l))
+				}
+			}
+		}
+		i = j
+	}
+	return out
+}
+
+// InvertHunks returns the inverse of hunks, from the destination to the source.
+// Applying the inverted hunks to the destination returns the source.
+func InvertHunks(hunks []Hunk) []Hunk {
+	if hunks == nil {
+		return nil
+	}
+	out := make([]Hunk, len(hunks))
+	for i, h := range hunks {
+		out[i] = Hunk{
+			Diffs:    Invert(h.Diffs),
+			SrcStart: h.DstStart,
+			SrcLines: h.DstLines,
+			DstStart: h.SrcStart,
+			DstLines: h.SrcLines,
+			Section:  h.Section,
+		}
+	}
+	return out
+}
+
+// InvertFilePatch returns the inverse of a file patch, from the destination
+// to the source.
+func InvertFilePatch(p FilePatch) FilePatch {
+	return FilePatch{
+		SrcHeader:    p.DstHeader,
+		DstHeader:    p.SrcHeader,
+		Hunks:        InvertHunks(p.Hunks),
+		SrcNoNewline: p.DstNoNewline,
+		DstNoNewline: p.SrcNoNewline,
+	}
+}
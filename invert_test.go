@@ -0,0 +1,102 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInvert(t *testing.T) {
+	type args struct {
+		diffs []DiffLine
+	}
+	tests := []struct {
+		name string
+		args args
+		want []DiffLine
+	}{
+		{
+			name: "Test nil diffs",
+			args: args{
+				diffs: nil,
+			},
+			want: nil,
+		},
+		{
+			name: "Test swapping insertions and deletions",
+			args: args{
+				diffs: []DiffLine{
+					{Text: "a", Type: Equal, SrcLine: 1, DstLine: 1},
+					{Text: "b", Type: Delete, SrcLine: 2},
+					{Text: "c", Type: Delete, SrcLine: 3},
+					{Text: "x", Type: Insert, DstLine: 2},
+					{Text: "d", Type: Equal, SrcLine: 4, DstLine: 3},
+					{Text: "y", Type: Insert, DstLine: 4},
+				},
+			},
+			want: []DiffLine{
+				{Text: "a", Type: Equal, SrcLine: 1, DstLine: 1},
+				{Text: "x", Type: Delete, SrcLine: 2},
+				{Text: "b", Type: Insert, DstLine: 2},
+				{Text: "c", Type: Insert, DstLine: 3},
+				{Text: "d", Type: Equal, SrcLine: 3, DstLine: 4},
+				{Text: "y", Type: Delete, SrcLine: 4},
+			},
+		},
+		{
+			name: "Test inverting segments",
+			args: args{
+				diffs: []DiffLine{
+					{Text: "ab", Type: Delete, Segments: []Segment{{Text: "a", Type: Equal}, {Text: "b", Type: Delete}}},
+				},
+			},
+			want: []DiffLine{
+				{Text: "ab", Type: Insert, Segments: []Segment{{Text: "a", Type: Equal}, {Text: "b", Type: Insert}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Invert(tt.args.diffs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Invert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvertUnifiedDiff(t *testing.T) {
+	a := strings.Split("the\nquick\nbrown\nchicken\njumps\nover\nthe\ndog", "\n")
+	b := strings.Split("the\nquick\nbrown\nfox\njumps\nover\nthe\nlazy\ndog", "\n")
+	got := UnifiedDiffText(Invert(Diff(a, b)))
+	if want := UnifiedDiffText(Diff(b, a)); got != want {
+		t.Errorf("UnifiedDiffText(Invert()) = %v, want %v", got, want)
+	}
+}
+
+// TestInvertHunksRoundTrip tests that applying inverted hunks to the
+// destination returns the source.
+func TestInvertHunksRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 500; n++ {
+		a := randomLines(r, r.Intn(40), 1+r.Intn(10))
+		b := randomLines(r, r.Intn(40), 1+r.Intn(10))
+		ctx := r.Intn(4)
+		got, err := Apply(b, InvertHunks(Hunks(Diff(a, b), ctx, ctx)))
+		if err != nil {
+			t.Fatalf("Apply(%v) error = %v", b, err)
+		}
+		if len(got) != len(a) || (len(a) > 0 && !reflect.DeepEqual(got, a)) {
+			t.Fatalf("Apply(%v, InvertHunks(Diff(%v))) = %v, want %v", b, a, got, a)
+		}
+	}
+}
+
+func TestInvertFilePatch(t *testing.T) {
+	p := FilePatch{SrcHeader: "a", DstHeader: "b", SrcNoNewline: true}
+	want := FilePatch{SrcHeader: "b", DstHeader: "a", DstNoNewline: true}
+	if got := InvertFilePatch(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("InvertFilePatch() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,248 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// String returns the name of a DiffType: "equal", "insert" or "delete".
+func (t DiffType) String() string {
+	switch t {
+	case Equal:
+		return "equal"
+	case Insert:
+		return "insert"
+	case Delete:
+		return "delete"
+	default:
+		return fmt.Sprintf("DiffType(%d)", int8(t))
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface. A DiffType is
+// written as its name.
+func (t DiffType) MarshalJSON() ([]byte, error) {
+	switch t {
+	case Equal, Insert, Delete:
+		return json.Marshal(t.String())
+	default:
+		return nil, fmt.Errorf("unknown DiffType %d", int8(t))
+	}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. A DiffType is
+// read from its name.
+func (t *DiffType) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	switch s {
+	case "equal":
+		*t = Equal
+	case "insert":
+		*t = Insert
+	case "delete":
+		*t = Delete
+	default:
+		return fmt.Errorf("unknown DiffType %q", s)
+	}
+	return nil
+}
+
+// segmentJSON is the JSON representation of a Segment.
+type segmentJSON struct {
+	Type DiffType `json:"type"`
+	Text string   `json:"text"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s Segment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(segmentJSON{Type: s.Type, Text: s.Text})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *Segment) UnmarshalJSON(data []byte) error {
+	var v segmentJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*s = Segment{Text: v.Text, Type: v.Type}
+	return nil
+}
+
+// diffLineJSON is the JSON representation of a DiffLine.
+type diffLineJSON struct {
+	Type     DiffType  `json:"type"`
+	Text     string    `json:"text"`
+	SrcLine  int       `json:"srcLine,omitempty"`
+	DstLine  int       `json:"dstLine,omitempty"`
+	Segments []Segment `json:"segments,omitempty"`
+	Move     int       `json:"move,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. Zero line numbers,
+// and empty segments and moves, are omitted.
+func (l DiffLine) MarshalJSON() ([]byte, error) {
+	return json.Marshal(diffLineJSON{
+		Type:     l.Type,
+		Text:     l.Text,
+		SrcLine:  l.SrcLine,
+		DstLine:  l.DstLine,
+		Segments: l.Segments,
+		Move:     l.Move,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (l *DiffLine) UnmarshalJSON(data []byte) error {
+	var v diffLineJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*l = DiffLine{
+		Text:     v.Text,
+		Type:     v.Type,
+		SrcLine:  v.SrcLine,
+		DstLine:  v.DstLine,
+		Segments: v.Segments,
+		Move:     v.Move,
+	}
+	return nil
+}
+
+// hunkJSON is the JSON representation of a Hunk.
+type hunkJSON struct {
+	SrcStart int        `json:"srcStart"`
+	SrcLines int        `json:"srcLines"`
+	DstStart int        `json:"dstStart"`
+	DstLines int        `json:"dstLines"`
+	Section  string     `json:"section,omitempty"`
+	Lines    []DiffLine `json:"lines"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The Diffs of a hunk
+// are written as "lines".
+func (h Hunk) MarshalJSON() ([]byte, error) {
+	lines := h.Diffs
+	if lines == nil {
+		lines = []DiffLine{}
+	}
+	return json.Marshal(hunkJSON{
+		SrcStart: h.SrcStart,
+		SrcLines: h.SrcLines,
+		DstStart: h.DstStart,
+		DstLines: h.DstLines,
+		Section:  h.Section,
+		Lines:    lines,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (h *Hunk) UnmarshalJSON(data []byte) error {
+	var v hunkJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*h = Hunk{
+		Diffs:    v.Lines,
+		SrcStart: v.SrcStart,
+		SrcLines: v.SrcLines,
+		DstStart: v.DstStart,
+		DstLines: v.DstLines,
+		Section:  v.Section,
+	}
+	return nil
+}
+
+// DiffStats represents the number of changed lines of a diff.
+type DiffStats struct {
+	Insertions int `json:"insertions"`
+	Deletions  int `json:"deletions"`
+}
+
+// Stats returns the number of inserted and deleted lines of a diff.
+func Stats(diffs []DiffLine) DiffStats {
+	s := DiffStats{}
+	for _, l := range diffs {
+		switch l.Type {
+		case Insert:
+			s.Insertions++
+		case Delete:
+			s.Deletions++
+		}
+	}
+	return s
+}
+
+// FileDiff represents the JSON document of the diff of a whole file.
+// A document has the following format, where the type of each line is
+// "equal", "insert" or "delete", and zero line numbers, and empty
+// segments, moves and sections, are omitted:
+//
+//	{
+//	  "srcHeader": "a.txt",
+//	  "dstHeader": "b.txt",
+//	  "hunks": [
+//	    {
+//	      "srcStart": 3,
+//	      "srcLines": 3,
+//	      "dstStart": 3,
+//	      "dstLines": 3,
+//	      "section": "func main() {",
+//	      "lines": [
+//	        {"type": "equal", "text": "brown", "srcLine": 3, "dstLine": 3},
+//	        {"type": "delete", "text": "chicken", "srcLine": 4},
+//	        {"type": "insert", "text": "fox", "dstLine": 4},
+//	        {"type": "equal", "text": "jumps", "srcLine": 5, "dstLine": 5}
+//	      ]
+//	    }
+//	  ],
+//	  "stats": {"insertions": 1, "deletions": 1}
+//	}
+//
+// The "srcNoNewline" and "dstNoNewline" fields are true if the source or
+// destination file has no newline at end of file, and omitted otherwise.
+type FileDiff struct {
+	SrcHeader    string    `json:"srcHeader"`
+	DstHeader    string    `json:"dstHeader"`
+	Hunks        []Hunk    `json:"hunks"`
+	Stats        DiffStats `json:"stats"`
+	SrcNoNewline bool      `json:"srcNoNewline,omitempty"`
+	DstNoNewline bool      `json:"dstNoNewline,omitempty"`
+}
+
+// NewFileDiff returns the JSON document of the hunks of a file patch,
+// with the stats of its hunks.
+func NewFileDiff(p FilePatch) FileDiff {
+	hunks := p.Hunks
+	if hunks == nil {
+		hunks = []Hunk{}
+	}
+	stats := DiffStats{}
+	for _, h := range hunks {
+		s := Stats(h.Diffs)
+		stats.Insertions += s.Insertions
+		stats.Deletions += s.Deletions
+	}
+	return FileDiff{
+		SrcHeader:    p.SrcHeader,
+		DstHeader:    p.DstHeader,
+		Hunks:        hunks,
+		Stats:        stats,
+		SrcNoNewline: p.SrcNoNewline,
+		DstNoNewline: p.DstNoNewline,
+	}
+}
+
+// FilePatch returns the file patch of a JSON document.
+func (d FileDiff) FilePatch() FilePatch {
+	return FilePatch{
+		SrcHeader:    d.SrcHeader,
+		DstHeader:    d.DstHeader,
+		Hunks:        d.Hunks,
+		SrcNoNewline: d.SrcNoNewline,
+		DstNoNewline: d.DstNoNewline,
+	}
+}
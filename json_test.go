@@ -0,0 +1,192 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffTypeJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		t    DiffType
+		want string
+	}{
+		{name: "Equal", t: Equal, want: `"equal"`},
+		{name: "Insert", t: Insert, want: `"insert"`},
+		{name: "Delete", t: Delete, want: `"delete"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.t)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+			var dt DiffType
+			if err := json.Unmarshal(got, &dt); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if dt != tt.t {
+				t.Errorf("json.Unmarshal() = %v, want %v", dt, tt.t)
+			}
+		})
+	}
+}
+
+func TestDiffTypeJSONErrors(t *testing.T) {
+	if _, err := json.Marshal(DiffType(2)); err == nil {
+		t.Errorf("json.Marshal(DiffType(2)) error = nil, want error")
+	}
+	var dt DiffType
+	for _, data := range []string{`"replace"`, `1`} {
+		if err := json.Unmarshal([]byte(data), &dt); err == nil {
+			t.Errorf("json.Unmarshal(%s) error = nil, want error", data)
+		}
+	}
+}
+
+func TestDiffLineJSON(t *testing.T) {
+	type args struct {
+		l DiffLine
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Equal",
+			args: args{
+				l: DiffLine{Text: "a", Type: Equal, SrcLine: 1, DstLine: 2},
+			},
+			want: `{"type":"equal","text":"a","srcLine":1,"dstLine":2}`,
+		},
+		{
+			name: "Delete",
+			args: args{
+				l: DiffLine{Text: "a", Type: Delete, SrcLine: 1},
+			},
+			want: `{"type":"delete","text":"a","srcLine":1}`,
+		},
+		{
+			name: "Insert with segments and move",
+			args: args{
+				l: DiffLine{
+					Text:     "a b",
+					Type:     Insert,
+					DstLine:  3,
+					Segments: []Segment{{Text: "a ", Type: Equal}, {Text: "b", Type: Insert}},
+					Move:     1,
+				},
+			},
+			want: `{"type":"insert","text":"a b","dstLine":3,` +
+				`"segments":[{"type":"equal","text":"a "},{"type":"insert","text":"b"}],"move":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.args.l)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+			var l DiffLine
+			if err := json.Unmarshal(got, &l); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(l, tt.args.l) {
+				t.Errorf("json.Unmarshal() = %v, want %v", l, tt.args.l)
+			}
+		})
+	}
+}
+
+func TestHunkJSON(t *testing.T) {
+	h := Hunk{
+		Diffs: []DiffLine{
+			{Text: "a", Type: Equal, SrcLine: 1, DstLine: 1},
+			{Text: "b", Type: Insert, DstLine: 2},
+		},
+		SrcStart: 1,
+		SrcLines: 1,
+		DstStart: 1,
+		DstLines: 2,
+		Section:  "func main() {",
+	}
+	want := `{"srcStart":1,"srcLines":1,"dstStart":1,"dstLines":2,"section":"func main() {",` +
+		`"lines":[{"type":"equal","text":"a","srcLine":1,"dstLine":1},{"type":"insert","text":"b","dstLine":2}]}`
+
+	got, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+	var u Hunk
+	if err := json.Unmarshal(got, &u); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(u, h) {
+		t.Errorf("json.Unmarshal() = %v, want %v", u, h)
+	}
+}
+
+func TestStats(t *testing.T) {
+	a := strings.Split("the\nquick\nbrown\nchicken\njumps", "\n")
+	b := strings.Split("the\nslow\nbrown\nfox\nfox\njumps", "\n")
+	want := DiffStats{Insertions: 3, Deletions: 2}
+	if got := Stats(Diff(a, b)); got != want {
+		t.Errorf("Stats() = %v, want %v", got, want)
+	}
+}
+
+func TestFileDiffJSON(t *testing.T) {
+	a := strings.Split("the\nquick\nbrown\nchicken\njumps\nover\nthe\ndog", "\n")
+	b := strings.Split("the\nquick\nbrown\nfox\njumps\nover\nthe\nlazy\ndog", "\n")
+	p := FilePatch{
+		SrcHeader:    "a.txt",
+		DstHeader:    "b.txt",
+		Hunks:        Hunks(Diff(a, b), 1, 1),
+		DstNoNewline: true,
+	}
+
+	want := `{"srcHeader":"a.txt","dstHeader":"b.txt","hunks":[` +
+		`{"srcStart":3,"srcLines":3,"dstStart":3,"dstLines":3,"lines":[` +
+		`{"type":"equal","text":"brown","srcLine":3,"dstLine":3},` +
+		`{"type":"delete","text":"chicken","srcLine":4},` +
+		`{"type":"insert","text":"fox","dstLine":4},` +
+		`{"type":"equal","text":"jumps","srcLine":5,"dstLine":5}]},` +
+		`{"srcStart":7,"srcLines":2,"dstStart":7,"dstLines":3,"lines":[` +
+		`{"type":"equal","text":"the","srcLine":7,"dstLine":7},` +
+		`{"type":"insert","text":"lazy","dstLine":8},` +
+		`{"type":"equal","text":"dog","srcLine":8,"dstLine":9}]}],` +
+		`"stats":{"insertions":2,"deletions":1},"dstNoNewline":true}`
+
+	got, err := json.Marshal(NewFileDiff(p))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+
+	var d FileDiff
+	if err := json.Unmarshal(got, &d); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(d.FilePatch(), p) {
+		t.Errorf("FilePatch() = %v, want %v", d.FilePatch(), p)
+	}
+
+	if got, _ := json.Marshal(NewFileDiff(FilePatch{})); string(got) != `{"srcHeader":"","dstHeader":"","hunks":[],"stats":{"insertions":0,"deletions":0}}` {
+		t.Errorf("json.Marshal() = %s, want empty hunks", got)
+	}
+}
@@ -1,6 +1,8 @@
 // Package patience implements the Patience Diff algorithm.
 package patience
 
+import "sort"
+
 // LCS computes the longest common subsequence of two string
 // slices and returns the index pairs of the LCS.
 func LCS(a, b []string) [][2]int {
@@ -45,6 +47,158 @@ func LCS(a, b []string) [][2]int {
 	return s
 }
 
+// LCSOptions represents the options for LCSWithOptions.
+type LCSOptions struct {
+	// LinearSpace computes the LCS using Hirschberg's algorithm, which
+	// requires O(len(a)+len(b)) space instead of O(len(a)*len(b)).
+	LinearSpace bool
+}
+
+// LCSWithOptions computes the longest common subsequence of two string
+// slices and returns the index pairs of the LCS.
+func LCSWithOptions(a, b []string, opts LCSOptions) [][2]int {
+	if !opts.LinearSpace {
+		return LCS(a, b)
+	}
+	return hirschberg(a, b, 0, 0, [][2]int{})
+}
+
+// lcsLengths returns the LCS lengths of slice a and each prefix of slice b.
+// If reverse is true, the LCS lengths of slice a and each suffix of slice b
+// are returned instead.
+func lcsLengths(a, b []string, reverse bool) []int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		for j := 1; j <= len(b); j++ {
+			var equal bool
+			if reverse {
+				equal = a[len(a)-1-i] == b[len(b)-j]
+			} else {
+				equal = a[i] == b[j-1]
+			}
+			if equal {
+				cur[j] = prev[j-1] + 1
+			} else {
+				cur[j] = max(prev[j], cur[j-1])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	if reverse {
+		for i, j := 0, len(prev)-1; i < j; i, j = i+1, j-1 {
+			prev[i], prev[j] = prev[j], prev[i]
+		}
+	}
+	return prev
+}
+
+// hirschberg appends the index pairs of the longest common subsequence
+// of two string slices to s using Hirschberg's algorithm. The offsets are
+// added to the indices of slices a and b respectively.
+func hirschberg(a, b []string, aOffset, bOffset int, s [][2]int) [][2]int {
+	switch {
+	case len(a) == 0 || len(b) == 0:
+		return s
+	case len(a) == 1:
+		for j, e := range b {
+			if e == a[0] {
+				return append(s, [2]int{aOffset, bOffset + j})
+			}
+		}
+		return s
+	}
+
+	// Split b where the LCS lengths of the halves of a are maximal.
+	mid := len(a) / 2
+	head := lcsLengths(a[:mid], b, false)
+	tail := lcsLengths(a[mid:], b, true)
+	k := 0
+	for j := range head {
+		if head[j]+tail[j] > head[k]+tail[k] {
+			k = j
+		}
+	}
+
+	s = hirschberg(a[:mid], b[:k], aOffset, bOffset, s)
+	return hirschberg(a[mid:], b[k:], aOffset+mid, bOffset+k, s)
+}
+
+// patienceLCS computes the longest common subsequence of two slices of
+// element IDs that contain only unique elements, and returns the index
+// pairs of the LCS. It uses patience sorting, requiring O(n log n) time
+// and O(n) space. The positions slice must be indexable by every ID and
+// contain only -1 values. It is left in the same state on return.
+func patienceLCS(a, b, positions []int) [][2]int {
+	for j, e := range b {
+		positions[e] = j
+	}
+
+	// Deal the common elements onto piles, in the order they appear in a.
+	// Each pile is kept in decreasing order of index in b, and each card
+	// links to the top card of the previous pile when it was dealt.
+	type card struct {
+		pair [2]int
+		prev int
+	}
+	cards := []card{}
+	piles := []int{}
+	for i, e := range a {
+		j := positions[e]
+		if j < 0 {
+			continue
+		}
+		p := sort.Search(len(piles), func(p int) bool {
+			return cards[piles[p]].pair[1] > j
+		})
+		prev := -1
+		if p > 0 {
+			prev = piles[p-1]
+		}
+		cards = append(cards, card{pair: [2]int{i, j}, prev: prev})
+		if p == len(piles) {
+			piles = append(piles, len(cards)-1)
+		} else {
+			piles[p] = len(cards) - 1
+		}
+	}
+
+	for _, e := range b {
+		positions[e] = -1
+	}
+
+	// Follow the links back from the top card of the last pile.
+	s := make([][2]int, len(piles))
+	c := -1
+	if len(piles) > 0 {
+		c = piles[len(piles)-1]
+	}
+	for k := len(s) - 1; k >= 0; k-- {
+		s[k] = cards[c].pair
+		c = cards[c].prev
+	}
+
+	return s
+}
+
+// LCSDiff returns the diff of two slices of strings computed from the
+// longest common subsequence of all of their elements.
+func LCSDiff(a, b []string) []DiffLine {
+	diffs := make([]DiffLine, 0, len(a)+len(b))
+	ga, gb := 0, 0
+	for _, ip := range LCS(a, b) {
+		diffs = appendDiffLines(diffs, a[ga:ip[0]], Delete)
+		diffs = appendDiffLines(diffs, b[gb:ip[1]], Insert)
+		diffs = append(diffs, DiffLine{Type: Equal, Text: a[ip[0]]})
+		ga = ip[0] + 1
+		gb = ip[1] + 1
+	}
+	diffs = appendDiffLines(diffs, a[ga:], Delete)
+	diffs = appendDiffLines(diffs, b[gb:], Insert)
+	numberLines(diffs)
+	return diffs
+}
+
 // max returns the maximum of two integers.
 // nolint:predeclared
 func max(a, b int) int {
@@ -2,6 +2,7 @@
 package patience
 
 import (
+	"math/rand"
 	"reflect"
 	"testing"
 )
@@ -62,3 +63,149 @@ func TestLCS(t *testing.T) {
 		})
 	}
 }
+
+func TestLCSWithOptions(t *testing.T) {
+	type args struct {
+		a    []string
+		b    []string
+		opts LCSOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want [][2]int
+	}{
+		{
+			name: "Test empty slice",
+			args: args{
+				a:    []string{},
+				b:    []string{"a"},
+				opts: LCSOptions{LinearSpace: true},
+			},
+			want: [][2]int{},
+		},
+		{
+			name: "Test identical slices",
+			args: args{
+				a:    []string{"a", "b", "c"},
+				b:    []string{"a", "b", "c"},
+				opts: LCSOptions{LinearSpace: true},
+			},
+			want: [][2]int{
+				{0, 0},
+				{1, 1},
+				{2, 2},
+			},
+		},
+		{
+			name: "Test slices of different lengths",
+			args: args{
+				a:    []string{"a", "z", "b", "c"},
+				b:    []string{"a", "b", "y", "w", "c"},
+				opts: LCSOptions{LinearSpace: true},
+			},
+			want: [][2]int{
+				{0, 0},
+				{2, 1},
+				{3, 4},
+			},
+		},
+		{
+			name: "Test without linear space",
+			args: args{
+				a:    []string{"a", "z", "b", "c"},
+				b:    []string{"a", "b", "y", "w", "c"},
+				opts: LCSOptions{},
+			},
+			want: [][2]int{
+				{0, 0},
+				{2, 1},
+				{3, 4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LCSWithOptions(tt.args.a, tt.args.b, tt.args.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LCSWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLCSLinearSpaceLength tests that the linear space LCS is a common
+// subsequence with the same length as the LCS.
+func TestLCSLinearSpaceLength(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		a := randomLines(r, r.Intn(30), 4)
+		b := randomLines(r, r.Intn(30), 4)
+		got := LCSWithOptions(a, b, LCSOptions{LinearSpace: true})
+		if want := LCS(a, b); len(got) != len(want) {
+			t.Fatalf("LCSWithOptions(%v, %v) has length %d, want %d", a, b, len(got), len(want))
+		}
+		for k, ip := range got {
+			if a[ip[0]] != b[ip[1]] || (k > 0 && (ip[0] <= got[k-1][0] || ip[1] <= got[k-1][1])) {
+				t.Fatalf("LCSWithOptions(%v, %v) = %v is not a common subsequence", a, b, got)
+			}
+		}
+	}
+}
+
+func Test_patienceLCS(t *testing.T) {
+	type args struct {
+		a []string
+		b []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want [][2]int
+	}{
+		{
+			name: "Test no common elements",
+			args: args{
+				a: []string{"a", "b"},
+				b: []string{"c", "d"},
+			},
+			want: [][2]int{},
+		},
+		{
+			name: "Test identical slices",
+			args: args{
+				a: []string{"a", "b", "c"},
+				b: []string{"a", "b", "c"},
+			},
+			want: [][2]int{
+				{0, 0},
+				{1, 1},
+				{2, 2},
+			},
+		},
+		{
+			name: "Test reordered elements",
+			args: args{
+				a: []string{"a", "b", "c", "d", "e", "f"},
+				b: []string{"c", "a", "d", "b", "e", "x", "f"},
+			},
+			want: [][2]int{
+				{2, 0},
+				{3, 2},
+				{4, 4},
+				{5, 6},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ia, ib, n := intern(tt.args.a, tt.args.b)
+			positions := make([]int, n)
+			for i := range positions {
+				positions[i] = -1
+			}
+			if got := patienceLCS(ia, ib, positions); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("patienceLCS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+// LineMap maps line numbers between the source and destination of a diff.
+// Lines that are equal in both map exactly. Lines that exist on only one
+// side map inexactly to the line of their nearest equal neighbour.
+type LineMap struct {
+	srcToDst, dstToSrc []int
+	srcExact, dstExact []bool
+}
+
+// nearestLines sets the mapping of each unmapped line to the mapping of
+// its nearest mapped line, preferring the preceding line on a tie.
+func nearestLines(lines []int) {
+	prev := make([]int, len(lines))
+	last := -1
+	for i := range lines {
+		if lines[i] > 0 {
+			last = i
+		}
+		prev[i] = last
+	}
+	next := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if prev[i] == i {
+			next = i
+			continue
+		}
+		p := prev[i]
+		switch {
+		case p < 0 && next < 0:
+			// There are no mapped lines.
+		case p < 0 || (next >= 0 && next-i < i-p):
+			lines[i] = lines[next]
+		default:
+			lines[i] = lines[p]
+		}
+	}
+}
+
+// NewLineMap returns a LineMap for a diff.
+func NewLineMap(diffs []DiffLine) *LineMap {
+	m := &LineMap{}
+	for _, l := range diffs {
+		switch l.Type {
+		case Equal:
+			m.srcToDst = append(m.srcToDst, len(m.dstToSrc)+1)
+			m.dstToSrc = append(m.dstToSrc, len(m.srcToDst))
+			m.srcExact = append(m.srcExact, true)
+			m.dstExact = append(m.dstExact, true)
+		case Delete:
+			m.srcToDst = append(m.srcToDst, 0)
+			m.srcExact = append(m.srcExact, false)
+		case Insert:
+			m.dstToSrc = append(m.dstToSrc, 0)
+			m.dstExact = append(m.dstExact, false)
+		}
+	}
+	nearestLines(m.srcToDst)
+	nearestLines(m.dstToSrc)
+	return m
+}
+
+// mapLine returns the mapping of a 1-based line number, and whether it is exact.
+func mapLine(lines []int, exact []bool, line int) (int, bool) {
+	if line < 1 || line > len(lines) {
+		return 0, false
+	}
+	return lines[line-1], exact[line-1]
+}
+
+// mapRange returns the mapping of an inclusive range of 1-based line
+// numbers, and whether every line in the range maps exactly.
+func mapRange(lines []int, exact []bool, start, end int) (int, int, bool) {
+	s, allExact := mapLine(lines, exact, start)
+	e, eExact := mapLine(lines, exact, end)
+	allExact = allExact && eExact && e-s == end-start
+	for line := start + 1; allExact && line < end; line++ {
+		allExact = exact[line-1]
+	}
+	return s, e, allExact
+}
+
+// SrcToDst returns the destination line number of a 1-based source line
+// number, and whether the mapping is exact. A deleted line maps to the
+// destination line of its nearest equal neighbour. Zero is returned if the
+// line is out of range or there are no equal lines.
+func (m *LineMap) SrcToDst(line int) (int, bool) {
+	return mapLine(m.srcToDst, m.srcExact, line)
+}
+
+// DstToSrc returns the source line number of a 1-based destination line
+// number, and whether the mapping is exact. An inserted line maps to the
+// source line of its nearest equal neighbour. Zero is returned if the
+// line is out of range or there are no equal lines.
+func (m *LineMap) DstToSrc(line int) (int, bool) {
+	return mapLine(m.dstToSrc, m.dstExact, line)
+}
+
+// SrcRangeToDst returns the destination line range of an inclusive range
+// of 1-based source line numbers. The mapping is exact if every line in
+// the range is unchanged.
+func (m *LineMap) SrcRangeToDst(start, end int) (int, int, bool) {
+	return mapRange(m.srcToDst, m.srcExact, start, end)
+}
+
+// DstRangeToSrc returns the source line range of an inclusive range of
+// 1-based destination line numbers. The mapping is exact if every line in
+// the range is unchanged.
+func (m *LineMap) DstRangeToSrc(start, end int) (int, int, bool) {
+	return mapRange(m.dstToSrc, m.dstExact, start, end)
+}
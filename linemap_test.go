@@ -0,0 +1,87 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"testing"
+)
+
+func TestLineMap(t *testing.T) {
+	// Source: a b c d e f
+	// Destination: a x c d y z f
+	m := NewLineMap([]DiffLine{
+		{Text: "a", Type: Equal},
+		{Text: "b", Type: Delete},
+		{Text: "x", Type: Insert},
+		{Text: "c", Type: Equal},
+		{Text: "d", Type: Equal},
+		{Text: "e", Type: Delete},
+		{Text: "y", Type: Insert},
+		{Text: "z", Type: Insert},
+		{Text: "f", Type: Equal},
+	})
+
+	type mapping struct {
+		line      int
+		want      int
+		wantExact bool
+	}
+	srcTests := []mapping{
+		{line: 0, want: 0, wantExact: false},
+		{line: 1, want: 1, wantExact: true},
+		{line: 2, want: 1, wantExact: false},
+		{line: 3, want: 3, wantExact: true},
+		{line: 5, want: 4, wantExact: false},
+		{line: 6, want: 7, wantExact: true},
+		{line: 7, want: 0, wantExact: false},
+	}
+	for _, tt := range srcTests {
+		if got, gotExact := m.SrcToDst(tt.line); got != tt.want || gotExact != tt.wantExact {
+			t.Errorf("SrcToDst(%d) = %v, %v, want %v, %v", tt.line, got, gotExact, tt.want, tt.wantExact)
+		}
+	}
+	dstTests := []mapping{
+		{line: 2, want: 1, wantExact: false},
+		{line: 4, want: 4, wantExact: true},
+		{line: 5, want: 4, wantExact: false},
+		{line: 6, want: 6, wantExact: false},
+		{line: 7, want: 6, wantExact: true},
+	}
+	for _, tt := range dstTests {
+		if got, gotExact := m.DstToSrc(tt.line); got != tt.want || gotExact != tt.wantExact {
+			t.Errorf("DstToSrc(%d) = %v, %v, want %v, %v", tt.line, got, gotExact, tt.want, tt.wantExact)
+		}
+	}
+
+	type rangeMapping struct {
+		start, end         int
+		wantStart, wantEnd int
+		wantExact          bool
+	}
+	rangeTests := []rangeMapping{
+		{start: 3, end: 4, wantStart: 3, wantEnd: 4, wantExact: true},
+		{start: 1, end: 3, wantStart: 1, wantEnd: 3, wantExact: false},
+		{start: 4, end: 6, wantStart: 4, wantEnd: 7, wantExact: false},
+	}
+	for _, tt := range rangeTests {
+		gotStart, gotEnd, gotExact := m.SrcRangeToDst(tt.start, tt.end)
+		if gotStart != tt.wantStart || gotEnd != tt.wantEnd || gotExact != tt.wantExact {
+			t.Errorf(
+				"SrcRangeToDst(%d, %d) = %v, %v, %v, want %v, %v, %v",
+				tt.start, tt.end, gotStart, gotEnd, gotExact, tt.wantStart, tt.wantEnd, tt.wantExact,
+			)
+		}
+	}
+	if gotStart, gotEnd, gotExact := m.DstRangeToSrc(5, 6); gotStart != 4 || gotEnd != 6 || gotExact {
+		t.Errorf("DstRangeToSrc(5, 6) = %v, %v, %v, want 4, 6, false", gotStart, gotEnd, gotExact)
+	}
+}
+
+func TestLineMapNoEqualLines(t *testing.T) {
+	m := NewLineMap([]DiffLine{
+		{Text: "a", Type: Delete},
+		{Text: "b", Type: Insert},
+	})
+	if got, gotExact := m.SrcToDst(1); got != 0 || gotExact {
+		t.Errorf("SrcToDst(1) = %v, %v, want 0, false", got, gotExact)
+	}
+}
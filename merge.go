@@ -0,0 +1,185 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import "strings"
+
+// ConflictStyle defines how conflicts are written in a merge.
+type ConflictStyle int8
+
+const (
+	// ConflictStyleMerge writes the ours and theirs sides of a conflict.
+	ConflictStyleMerge ConflictStyle = iota
+	// ConflictStyleDiff3 also writes the base side of a conflict.
+	ConflictStyleDiff3
+	// ConflictStyleZdiff3 writes the base side of a conflict, and moves
+	// lines common to the start or end of both sides out of the conflict.
+	ConflictStyleZdiff3
+)
+
+// MergeOptions represents the options for MergeWithOptions.
+type MergeOptions struct {
+	// Style is the style in which conflicts are written.
+	Style ConflictStyle
+	// OursLabel is the label following the "<<<<<<<" marker.
+	OursLabel string
+	// BaseLabel is the label following the "|||||||" marker.
+	BaseLabel string
+	// TheirsLabel is the label following the ">>>>>>>" marker.
+	TheirsLabel string
+}
+
+// MergeResult represents the result of a three-way merge.
+type MergeResult struct {
+	// Lines are the merged lines, including any conflict markers.
+	Lines []string
+	// Conflicts is the number of conflicts.
+	Conflicts int
+}
+
+// change represents the replacement of base[baseStart:baseEnd] with lines.
+type change struct {
+	baseStart int
+	baseEnd   int
+	lines     []string
+}
+
+// changes returns the changes of a diff from base to another version.
+func changes(diffs []DiffLine) []change {
+	cs := []change{}
+	bi := 0
+	for i := 0; i < len(diffs); {
+		if diffs[i].Type == Equal {
+			bi++
+			i++
+			continue
+		}
+		c := change{baseStart: bi, lines: []string{}}
+		for ; i < len(diffs) && diffs[i].Type != Equal; i++ {
+			if diffs[i].Type == Delete {
+				bi++
+			} else {
+				c.lines = append(c.lines, diffs[i].Text)
+			}
+		}
+		c.baseEnd = bi
+		cs = append(cs, c)
+	}
+	return cs
+}
+
+// applyChanges returns base[lo:hi] with changes applied.
+func applyChanges(base []string, cs []change, lo, hi int) []string {
+	lines := []string{}
+	for _, c := range cs {
+		lines = append(lines, base[lo:c.baseStart]...)
+		lines = append(lines, c.lines...)
+		lo = c.baseEnd
+	}
+	return append(lines, base[lo:hi]...)
+}
+
+// equalLines reports whether two slices of lines are equal.
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// marker returns a conflict marker followed by an optional label.
+func marker(m, label string) string {
+	if len(label) == 0 {
+		return m
+	}
+	return m + " " + label
+}
+
+// MergeWithOptions returns the three-way merge of two versions derived from
+// a common base. Changes from base to ours and from base to theirs are found
+// with Diff. Changes that do not overlap or touch are applied, as are
+// identical changes on both sides. Other changes are written as conflicts.
+func MergeWithOptions(base, ours, theirs []string, opts MergeOptions) MergeResult {
+	co, ct := changes(Diff(base, ours)), changes(Diff(base, theirs))
+	res := MergeResult{Lines: []string{}}
+	pos := 0
+	for len(co) > 0 || len(ct) > 0 {
+		// Start a region with the first change of either side, and extend it
+		// with the changes of both sides that overlap or touch it.
+		lo, hi := 0, 0
+		switch {
+		case len(ct) == 0 || (len(co) > 0 && co[0].baseStart <= ct[0].baseStart):
+			lo, hi = co[0].baseStart, co[0].baseEnd
+		default:
+			lo, hi = ct[0].baseStart, ct[0].baseEnd
+		}
+		no, nt := 0, 0
+		for {
+			if no < len(co) && co[no].baseStart <= hi {
+				hi = max(hi, co[no].baseEnd)
+				no++
+				continue
+			}
+			if nt < len(ct) && ct[nt].baseStart <= hi {
+				hi = max(hi, ct[nt].baseEnd)
+				nt++
+				continue
+			}
+			break
+		}
+
+		res.Lines = append(res.Lines, base[pos:lo]...)
+		o := applyChanges(base, co[:no], lo, hi)
+		t := applyChanges(base, ct[:nt], lo, hi)
+		switch {
+		case nt == 0:
+			res.Lines = append(res.Lines, o...)
+		case no == 0 || equalLines(o, t):
+			res.Lines = append(res.Lines, t...)
+		default:
+			res.Lines = appendConflict(res.Lines, base[lo:hi], o, t, opts)
+			res.Conflicts++
+		}
+		pos = hi
+		co, ct = co[no:], ct[nt:]
+	}
+	res.Lines = append(res.Lines, base[pos:]...)
+	return res
+}
+
+// appendConflict appends a conflict between the ours and theirs versions of
+// a region of base to lines.
+func appendConflict(lines, base, ours, theirs []string, opts MergeOptions) []string {
+	var head, tail int
+	if opts.Style == ConflictStyleZdiff3 {
+		for head < len(ours) && head < len(theirs) && ours[head] == theirs[head] {
+			head++
+		}
+		for tail < len(ours)-head && tail < len(theirs)-head &&
+			ours[len(ours)-1-tail] == theirs[len(theirs)-1-tail] {
+			tail++
+		}
+	}
+
+	lines = append(lines, ours[:head]...)
+	lines = append(lines, marker(strings.Repeat("<", 7), opts.OursLabel))
+	lines = append(lines, ours[head:len(ours)-tail]...)
+	if opts.Style != ConflictStyleMerge {
+		lines = append(lines, marker(strings.Repeat("|", 7), opts.BaseLabel))
+		lines = append(lines, base...)
+	}
+	lines = append(lines, strings.Repeat("=", 7))
+	lines = append(lines, theirs[head:len(theirs)-tail]...)
+	lines = append(lines, marker(strings.Repeat(">", 7), opts.TheirsLabel))
+	return append(lines, ours[len(ours)-tail:]...)
+}
+
+// Merge returns the three-way merge of two versions derived from a common
+// base, writing conflicts in the merge style.
+func Merge(base, ours, theirs []string) MergeResult {
+	return MergeWithOptions(base, ours, theirs, MergeOptions{})
+}
@@ -0,0 +1,128 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_changes(t *testing.T) {
+	diffs := []DiffLine{
+		{Text: "a", Type: Equal},
+		{Text: "b", Type: Delete},
+		{Text: "x", Type: Insert},
+		{Text: "c", Type: Equal},
+		{Text: "y", Type: Insert},
+		{Text: "d", Type: Delete},
+	}
+	want := []change{
+		{baseStart: 1, baseEnd: 2, lines: []string{"x"}},
+		{baseStart: 3, baseEnd: 4, lines: []string{"y"}},
+	}
+	if got := changes(diffs); !reflect.DeepEqual(got, want) {
+		t.Errorf("changes() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeWithOptions(t *testing.T) {
+	base := strings.Split("a\nb\nc\nd\ne\nf\ng", "\n")
+	type args struct {
+		ours   []string
+		theirs []string
+		opts   MergeOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want MergeResult
+	}{
+		{
+			name: "Test no changes",
+			args: args{
+				ours:   base,
+				theirs: base,
+			},
+			want: MergeResult{Lines: base},
+		},
+		{
+			name: "Test non-overlapping changes",
+			args: args{
+				ours:   strings.Split("a\nB\nc\nd\ne\nf\ng", "\n"),
+				theirs: strings.Split("a\nb\nc\nd\ne\nF\ng\nh", "\n"),
+			},
+			want: MergeResult{Lines: strings.Split("a\nB\nc\nd\ne\nF\ng\nh", "\n")},
+		},
+		{
+			name: "Test identical changes",
+			args: args{
+				ours:   strings.Split("a\nb\nX\nd\ne\nf\ng", "\n"),
+				theirs: strings.Split("a\nb\nX\nd\ne\nf\ng", "\n"),
+			},
+			want: MergeResult{Lines: strings.Split("a\nb\nX\nd\ne\nf\ng", "\n")},
+		},
+		{
+			name: "Test conflict in merge style",
+			args: args{
+				ours:   strings.Split("a\nb\nX\nY\ne\nf\ng", "\n"),
+				theirs: strings.Split("a\nb\nX\nZ\ne\nf\ng", "\n"),
+				opts:   MergeOptions{OursLabel: "ours", TheirsLabel: "theirs"},
+			},
+			want: MergeResult{
+				Lines:     strings.Split("a\nb\n<<<<<<< ours\nX\nY\n=======\nX\nZ\n>>>>>>> theirs\ne\nf\ng", "\n"),
+				Conflicts: 1,
+			},
+		},
+		{
+			name: "Test conflict in diff3 style",
+			args: args{
+				ours:   strings.Split("a\nb\nX\nY\ne\nf\ng", "\n"),
+				theirs: strings.Split("a\nb\nX\nZ\ne\nf\ng", "\n"),
+				opts:   MergeOptions{Style: ConflictStyleDiff3, BaseLabel: "base"},
+			},
+			want: MergeResult{
+				Lines:     strings.Split("a\nb\n<<<<<<<\nX\nY\n||||||| base\nc\nd\n=======\nX\nZ\n>>>>>>>\ne\nf\ng", "\n"),
+				Conflicts: 1,
+			},
+		},
+		{
+			name: "Test conflict in zdiff3 style",
+			args: args{
+				ours:   strings.Split("a\nb\nX\nY\ne\nf\ng", "\n"),
+				theirs: strings.Split("a\nb\nX\nZ\ne\nf\ng", "\n"),
+				opts:   MergeOptions{Style: ConflictStyleZdiff3},
+			},
+			want: MergeResult{
+				Lines:     strings.Split("a\nb\nX\n<<<<<<<\nY\n|||||||\nc\nd\n=======\nZ\n>>>>>>>\ne\nf\ng", "\n"),
+				Conflicts: 1,
+			},
+		},
+		{
+			name: "Test deletion and change conflict",
+			args: args{
+				ours:   strings.Split("a\nb\nc\nd\nf\ng", "\n"),
+				theirs: strings.Split("a\nb\nc\nd\nE\nf\ng", "\n"),
+			},
+			want: MergeResult{
+				Lines:     strings.Split("a\nb\nc\nd\n<<<<<<<\n=======\nE\n>>>>>>>\nf\ng", "\n"),
+				Conflicts: 1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MergeWithOptions(base, tt.args.ours, tt.args.theirs, tt.args.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := []string{"a", "b", "c"}
+	got := Merge(base, []string{"x", "a", "b", "c"}, []string{"a", "b", "c", "y"})
+	want := MergeResult{Lines: []string{"x", "a", "b", "c", "y"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %v, want %v", got, want)
+	}
+}
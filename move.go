@@ -0,0 +1,89 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+// defaultMinMoveLines is the default minimum number of lines of a moved block.
+const defaultMinMoveLines = 3
+
+// MoveOptions represents the options for DetectMovesWithOptions.
+type MoveOptions struct {
+	// MinLines is the minimum number of lines of a moved block.
+	// If zero, it defaults to 3.
+	MinLines int
+	// IgnoreWhitespace ignores all white space when comparing lines, so that
+	// blocks that were moved and reindented are detected.
+	IgnoreWhitespace bool
+}
+
+// DetectMovesWithOptions returns a copy of a diff with the Move of moved
+// lines set. A block of deleted lines is moved if the same lines are
+// inserted elsewhere in the diff. Blocks are found greedily, in the order
+// of the deleted lines, choosing the longest matching inserted lines.
+func DetectMovesWithOptions(diffs []DiffLine, opts MoveOptions) []DiffLine {
+	if diffs == nil {
+		return nil
+	}
+	minLines := opts.MinLines
+	if minLines <= 0 {
+		minLines = defaultMinMoveLines
+	}
+	key := func(s string) string { return s }
+	if opts.IgnoreWhitespace {
+		key = DiffOptions{IgnoreAllSpace: true}.normalize
+	}
+
+	out := make([]DiffLine, len(diffs))
+	copy(out, diffs)
+
+	// Index the inserted lines by key.
+	inserted := make(map[string][]int)
+	for i, l := range out {
+		if l.Type == Insert {
+			k := key(l.Text)
+			inserted[k] = append(inserted[k], i)
+		}
+	}
+
+	// matchLength returns the number of consecutive deleted lines from d
+	// that match consecutive unmoved inserted lines from i.
+	matchLength := func(d, i int) int {
+		n := 0
+		for d+n < len(out) && i+n < len(out) &&
+			out[d+n].Type == Delete && out[d+n].Move == 0 &&
+			out[i+n].Type == Insert && out[i+n].Move == 0 &&
+			key(out[d+n].Text) == key(out[i+n].Text) {
+			n++
+		}
+		return n
+	}
+
+	move := 0
+	for d := 0; d < len(out); {
+		if out[d].Type != Delete {
+			d++
+			continue
+		}
+		best, bestLen := 0, 0
+		for _, i := range inserted[key(out[d].Text)] {
+			if n := matchLength(d, i); n > bestLen {
+				best, bestLen = i, n
+			}
+		}
+		if bestLen < minLines {
+			d++
+			continue
+		}
+		move++
+		for k := 0; k < bestLen; k++ {
+			out[d+k].Move = move
+			out[best+k].Move = move
+		}
+		d += bestLen
+	}
+	return out
+}
+
+// DetectMoves returns a copy of a diff with the Move of lines set for
+// blocks of at least 3 lines that were moved.
+func DetectMoves(diffs []DiffLine) []DiffLine {
+	return DetectMovesWithOptions(diffs, MoveOptions{})
+}
@@ -0,0 +1,108 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectMoves(t *testing.T) {
+	a := strings.Split("func a() {\n\treturn 1\n}\n\nfunc b() {\n\treturn 2\n}\n\nfunc c() {}", "\n")
+	b := strings.Split("func b() {\n\treturn 2\n}\n\nfunc c() {}\nfunc a() {\n\treturn 1\n}\n", "\n")
+	got := DetectMoves(Diff(a, b))
+	want := []DiffLine{
+		{Text: "func a() {", Type: Delete, SrcLine: 1, Move: 1},
+		{Text: "\treturn 1", Type: Delete, SrcLine: 2, Move: 1},
+		{Text: "}", Type: Delete, SrcLine: 3, Move: 1},
+		{Text: "", Type: Delete, SrcLine: 4, Move: 1},
+		{Text: "func b() {", Type: Equal, SrcLine: 5, DstLine: 1},
+		{Text: "\treturn 2", Type: Equal, SrcLine: 6, DstLine: 2},
+		{Text: "}", Type: Equal, SrcLine: 7, DstLine: 3},
+		{Text: "", Type: Equal, SrcLine: 8, DstLine: 4},
+		{Text: "func c() {}", Type: Equal, SrcLine: 9, DstLine: 5},
+		{Text: "func a() {", Type: Insert, DstLine: 6, Move: 1},
+		{Text: "\treturn 1", Type: Insert, DstLine: 7, Move: 1},
+		{Text: "}", Type: Insert, DstLine: 8, Move: 1},
+		{Text: "", Type: Insert, DstLine: 9, Move: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectMoves() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectMovesWithOptions(t *testing.T) {
+	type args struct {
+		diffs []DiffLine
+		opts  MoveOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want []int
+	}{
+		{
+			name: "Test nil diffs",
+			args: args{
+				diffs: nil,
+			},
+			want: nil,
+		},
+		{
+			name: "Test block smaller than the minimum",
+			args: args{
+				diffs: []DiffLine{
+					{Text: "x", Type: Delete},
+					{Text: "y", Type: Delete},
+					{Text: "z", Type: Equal},
+					{Text: "x", Type: Insert},
+					{Text: "y", Type: Insert},
+				},
+			},
+			want: []int{0, 0, 0, 0, 0},
+		},
+		{
+			name: "Test minimum lines option",
+			args: args{
+				diffs: []DiffLine{
+					{Text: "x", Type: Delete},
+					{Text: "y", Type: Delete},
+					{Text: "z", Type: Equal},
+					{Text: "x", Type: Insert},
+					{Text: "y", Type: Insert},
+				},
+				opts: MoveOptions{MinLines: 2},
+			},
+			want: []int{1, 1, 0, 1, 1},
+		},
+		{
+			name: "Test ignoring white space",
+			args: args{
+				diffs: []DiffLine{
+					{Text: "x", Type: Insert},
+					{Text: "\ty", Type: Insert},
+					{Text: "z", Type: Equal},
+					{Text: "x", Type: Delete},
+					{Text: "    y", Type: Delete},
+					{Text: "a", Type: Delete},
+					{Text: "x", Type: Insert},
+					{Text: "\ty", Type: Insert},
+				},
+				opts: MoveOptions{MinLines: 2, IgnoreWhitespace: true},
+			},
+			want: []int{1, 1, 0, 1, 1, 0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectMovesWithOptions(tt.args.diffs, tt.args.opts)
+			var moves []int
+			for _, l := range got {
+				moves = append(moves, l.Move)
+			}
+			if !reflect.DeepEqual(moves, tt.want) {
+				t.Errorf("DetectMovesWithOptions() moves = %v, want %v", moves, tt.want)
+			}
+		})
+	}
+}
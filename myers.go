@@ -0,0 +1,181 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import "context"
+
+// myers holds the state of a linear space Myers diff.
+type myers[T any] struct {
+	ctx    context.Context
+	a, b   []T
+	ia, ib []int
+	diffs  []Edit[T]
+	vf, vb []int
+	offset int
+}
+
+// middleSnake returns the start and end points of the middle snake of the
+// shortest edit script between a[aLo:aHi] and b[bLo:bHi]. An error is
+// returned if the context is done.
+func (d *myers[T]) middleSnake(aLo, aHi, bLo, bHi int) (int, int, int, int, error) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta&1 != 0
+	vf, vb, o := d.vf, d.vb, d.offset
+	vf[o+1] = 0
+	vb[o+1] = 0
+
+	for e := 0; e <= (n+m+1)/2; e++ {
+		if err := done(d.ctx); err != nil {
+			return 0, 0, 0, 0, err
+		}
+
+		// Extend the furthest reaching forward paths.
+		for k := -e; k <= e; k += 2 {
+			var x int
+			if k == -e || (k != e && vf[o+k-1] < vf[o+k+1]) {
+				x = vf[o+k+1]
+			} else {
+				x = vf[o+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.ia[aLo+x] == d.ib[bLo+y] {
+				x++
+				y++
+			}
+			vf[o+k] = x
+			if odd && delta-k >= -(e-1) && delta-k <= e-1 && x+vb[o+delta-k] >= n {
+				return aLo + sx, bLo + sy, aLo + x, bLo + y, nil
+			}
+		}
+
+		// Extend the furthest reaching reverse paths.
+		for k := -e; k <= e; k += 2 {
+			var x int
+			if k == -e || (k != e && vb[o+k-1] < vb[o+k+1]) {
+				x = vb[o+k+1]
+			} else {
+				x = vb[o+k-1] + 1
+			}
+			y := x - k
+			sx, sy := x, y
+			for x < n && y < m && d.ia[aHi-1-x] == d.ib[bHi-1-y] {
+				x++
+				y++
+			}
+			vb[o+k] = x
+			if !odd && delta-k >= -e && delta-k <= e && x+vf[o+delta-k] >= n {
+				return aHi - x, bHi - y, aHi - sx, bHi - sy, nil
+			}
+		}
+	}
+
+	// Unreachable, the paths always overlap within (n+m+1)/2 steps.
+	panic("no middle snake found")
+}
+
+// compare appends the diff of a[aLo:aHi] and b[bLo:bHi].
+func (d *myers[T]) compare(aLo, aHi, bLo, bHi int) error {
+	// Find equal elements at the head of the ranges.
+	for aLo < aHi && bLo < bHi && d.ia[aLo] == d.ib[bLo] {
+		d.diffs = append(d.diffs, Edit[T]{Type: Equal, Value: d.a[aLo]})
+		aLo++
+		bLo++
+	}
+
+	// Find equal elements at the tail of the ranges.
+	tail := aHi
+	for aLo < aHi && bLo < bHi && d.ia[aHi-1] == d.ib[bHi-1] {
+		aHi--
+		bHi--
+	}
+
+	switch {
+	case aLo == aHi:
+		d.diffs = appendEdits(d.diffs, d.b[bLo:bHi], Insert)
+	case bLo == bHi:
+		d.diffs = appendEdits(d.diffs, d.a[aLo:aHi], Delete)
+	default:
+		// Divide at the middle snake and conquer.
+		x1, y1, x2, y2, err := d.middleSnake(aLo, aHi, bLo, bHi)
+		if err != nil {
+			return err
+		}
+		if err := d.compare(aLo, x1, bLo, y1); err != nil {
+			return err
+		}
+		d.diffs = appendEdits(d.diffs, d.a[x1:x2], Equal)
+		if err := d.compare(x2, aHi, y2, bHi); err != nil {
+			return err
+		}
+	}
+
+	d.diffs = appendEdits(d.diffs, d.a[aHi:tail], Equal)
+	return nil
+}
+
+// orderChanges reorders each block of adjacent deletions and insertions of
+// a slice of edits in place, so that the deletions precede the insertions.
+func orderChanges[T any](edits []Edit[T]) {
+	inss := []Edit[T]{}
+	for i := 0; i < len(edits); {
+		if edits[i].Type == Equal {
+			i++
+			continue
+		}
+		j, k := i, i
+		inss = inss[:0]
+		for ; j < len(edits) && edits[j].Type != Equal; j++ {
+			if edits[j].Type == Delete {
+				edits[k] = edits[j]
+				k++
+			} else {
+				inss = append(inss, edits[j])
+			}
+		}
+		copy(edits[k:j], inss)
+		i = j
+	}
+}
+
+// myersDiff appends the Myers diff of two slices of elements to diffs,
+// comparing the element IDs of each slice. Deletions precede insertions
+// in each block of changes. An error is returned if the context is done
+// before the diff is complete.
+func myersDiff[T any](ctx context.Context, diffs []Edit[T], a, b []T, ia, ib []int) ([]Edit[T], error) {
+	offset := (len(a)+len(b)+1)/2 + 1
+	d := &myers[T]{
+		ctx:    ctx,
+		a:      a,
+		b:      b,
+		ia:     ia,
+		ib:     ib,
+		diffs:  diffs,
+		vf:     make([]int, 2*offset+1),
+		vb:     make([]int, 2*offset+1),
+		offset: offset,
+	}
+	if err := d.compare(0, len(a), 0, len(b)); err != nil {
+		return nil, err
+	}
+
+	// Order the changes from the start of the block of changes that the
+	// diff appended to.
+	start := len(diffs)
+	for start > 0 && diffs[start-1].Type != Equal {
+		start--
+	}
+	orderChanges(d.diffs[start:])
+	return d.diffs, nil
+}
+
+// MyersDiff returns the Myers diff of two slices of strings.
+// It computes a minimal diff in O((N+M)D) time and linear space.
+func MyersDiff(a, b []string) []DiffLine {
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+	ia, ib, _ := intern(a, b)
+	edits, _ := myersDiff(context.Background(), make([]Edit[string], 0, len(a)+len(b)), a, b, ia, ib)
+	return toDiffLines(edits)
+}
@@ -0,0 +1,125 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMyersDiff(t *testing.T) {
+	type args struct {
+		a []string
+		b []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []DiffLine
+	}{
+		{
+			name: "Test empty slices",
+			args: args{
+				a: []string{},
+				b: []string{},
+			},
+			want: nil,
+		},
+		{
+			name: "Test empty slice a",
+			args: args{
+				a: []string{},
+				b: []string{"a"},
+			},
+			want: []DiffLine{
+				{Text: "a", Type: Insert, DstLine: 1},
+			},
+		},
+		{
+			name: "Test empty slice b",
+			args: args{
+				a: []string{"a"},
+				b: []string{},
+			},
+			want: []DiffLine{
+				{Text: "a", Type: Delete, SrcLine: 1},
+			},
+		},
+		{
+			name: "Test no diff",
+			args: args{
+				a: []string{"a", "b"},
+				b: []string{"a", "b"},
+			},
+			want: []DiffLine{
+				{Text: "a", Type: Equal, SrcLine: 1, DstLine: 1},
+				{Text: "b", Type: Equal, SrcLine: 2, DstLine: 2},
+			},
+		},
+		{
+			name: "Test classic example",
+			args: args{
+				a: []string{"a", "b", "c", "a", "b", "b", "a"},
+				b: []string{"c", "b", "a", "b", "a", "c"},
+			},
+			want: []DiffLine{
+				{Text: "a", Type: Delete, SrcLine: 1},
+				{Text: "c", Type: Insert, DstLine: 1},
+				{Text: "b", Type: Equal, SrcLine: 2, DstLine: 2},
+				{Text: "c", Type: Delete, SrcLine: 3},
+				{Text: "a", Type: Equal, SrcLine: 4, DstLine: 3},
+				{Text: "b", Type: Equal, SrcLine: 5, DstLine: 4},
+				{Text: "b", Type: Delete, SrcLine: 6},
+				{Text: "a", Type: Equal, SrcLine: 7, DstLine: 5},
+				{Text: "c", Type: Insert, DstLine: 6},
+			},
+		},
+		{
+			name: "Test deletions precede insertions",
+			args: args{
+				a: []string{"lD"},
+				b: []string{"chgb"},
+			},
+			want: []DiffLine{
+				{Text: "lD", Type: Delete, SrcLine: 1},
+				{Text: "chgb", Type: Insert, DstLine: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MyersDiff(tt.args.a, tt.args.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MyersDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMyersDiffOrder tests that deletions precede insertions in every
+// block of changes.
+func TestMyersDiffOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 500; n++ {
+		a := randomLines(r, r.Intn(30), 6)
+		b := randomLines(r, r.Intn(30), 6)
+		diffs := MyersDiff(a, b)
+		for i := 1; i < len(diffs); i++ {
+			if diffs[i-1].Type == Insert && diffs[i].Type == Delete {
+				t.Fatalf("MyersDiff(%v, %v) = %v, insertion precedes deletion", a, b, diffs)
+			}
+		}
+	}
+}
+
+// TestMyersDiffMinimal tests that Myers diffs are as short as LCS diffs.
+func TestMyersDiffMinimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		a := randomLines(r, r.Intn(30), 4)
+		b := randomLines(r, r.Intn(30), 4)
+		got, want := MyersDiff(a, b), LCSDiff(a, b)
+		if len(got) != len(want) {
+			t.Fatalf("MyersDiff(%v, %v) has %d lines, want %d", a, b, len(got), len(want))
+		}
+	}
+}
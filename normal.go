@@ -0,0 +1,88 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// editBlock represents a block of adjacent deletions and insertions of a
+// diff, with the 0-based half-open ranges of the lines in the source and
+// destination.
+type editBlock struct {
+	srcStart, srcEnd int
+	dstStart, dstEnd int
+	deleted          []string
+	inserted         []string
+}
+
+// editBlocks returns the blocks of adjacent deletions and insertions of a diff.
+func editBlocks(diffs []DiffLine) []editBlock {
+	blocks := []editBlock{}
+	src, dst := 0, 0
+	for i := 0; i < len(diffs); {
+		if diffs[i].Type == Equal {
+			src++
+			dst++
+			i++
+			continue
+		}
+		b := editBlock{srcStart: src, dstStart: dst}
+		for ; i < len(diffs) && diffs[i].Type != Equal; i++ {
+			if diffs[i].Type == Delete {
+				b.deleted = append(b.deleted, diffs[i].Text)
+				src++
+			} else {
+				b.inserted = append(b.inserted, diffs[i].Text)
+				dst++
+			}
+		}
+		b.srcEnd, b.dstEnd = src, dst
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// command returns the command letter of a block: "a" to add, "d" to
+// delete, or "c" to change lines.
+func (b editBlock) command() string {
+	switch {
+	case len(b.deleted) == 0:
+		return "a"
+	case len(b.inserted) == 0:
+		return "d"
+	default:
+		return "c"
+	}
+}
+
+// normalRange returns a 0-based half-open line range in normal format.
+// A range of one line is its line number, and an empty range is the line
+// before the range.
+func normalRange(start, end int) string {
+	if end-start <= 1 {
+		return strconv.Itoa(end)
+	}
+	return fmt.Sprintf("%d,%d", start+1, end)
+}
+
+// NormalDiffText returns the diff text in the normal format of diff, such
+// as "3c3" followed by the deleted lines prefixed with "< ", a "---"
+// separator, and the inserted lines prefixed with "> ".
+func NormalDiffText(diffs []DiffLine) string {
+	s := []string{}
+	for _, b := range editBlocks(diffs) {
+		s = append(s, normalRange(b.srcStart, b.srcEnd)+b.command()+normalRange(b.dstStart, b.dstEnd))
+		for _, l := range b.deleted {
+			s = append(s, "< "+l)
+		}
+		if len(b.deleted) > 0 && len(b.inserted) > 0 {
+			s = append(s, "---")
+		}
+		for _, l := range b.inserted {
+			s = append(s, "> "+l)
+		}
+	}
+	return strings.Join(s, "\n")
+}
@@ -0,0 +1,67 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalDiffText(t *testing.T) {
+	type args struct {
+		a []string
+		b []string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Equal",
+			args: args{
+				a: []string{"a", "b"},
+				b: []string{"a", "b"},
+			},
+			want: "",
+		},
+		{
+			name: "Change and add",
+			args: args{
+				a: strings.Split("the\nquick\nbrown\nchicken\njumps\nover\nthe\ndog", "\n"),
+				b: strings.Split("the\nquick\nbrown\nfox\njumps\nover\nthe\nlazy\ndog", "\n"),
+			},
+			want: "4c4\n< chicken\n---\n> fox\n7a8\n> lazy",
+		},
+		{
+			name: "Delete",
+			args: args{
+				a: []string{"a", "b", "c", "d"},
+				b: []string{"a", "d"},
+			},
+			want: "2,3d1\n< b\n< c",
+		},
+		{
+			name: "Add and delete at the start and end",
+			args: args{
+				a: []string{"a", "b", "c"},
+				b: []string{"x", "y", "a", "b"},
+			},
+			want: "0a1,2\n> x\n> y\n3d4\n< c",
+		},
+		{
+			name: "Change ranges",
+			args: args{
+				a: []string{"a", "b", "c"},
+				b: []string{"x"},
+			},
+			want: "1,3c1\n< a\n< b\n< c\n---\n> x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalDiffText(Diff(tt.args.a, tt.args.b)); got != tt.want {
+				t.Errorf("NormalDiffText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DiffOptions represents the options for DiffWithOptions.
+type DiffOptions struct {
+	// IgnoreAllSpace ignores all white space when comparing lines (diff -w).
+	IgnoreAllSpace bool
+	// IgnoreSpaceChange ignores changes in the amount of white space when
+	// comparing lines, including white space at line end (diff -b).
+	IgnoreSpaceChange bool
+	// IgnoreTrailingSpace ignores white space at line end when comparing lines (diff -Z).
+	IgnoreTrailingSpace bool
+}
+
+// normalize returns the line as it is compared under the options.
+func (o DiffOptions) normalize(s string) string {
+	switch {
+	case o.IgnoreAllSpace:
+		return strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, s)
+	case o.IgnoreSpaceChange:
+		// Collapse each run of white space to a single space.
+		var sb strings.Builder
+		space := false
+		for _, r := range strings.TrimRightFunc(s, unicode.IsSpace) {
+			if unicode.IsSpace(r) {
+				space = true
+				continue
+			}
+			if space {
+				sb.WriteByte(' ')
+				space = false
+			}
+			sb.WriteRune(r)
+		}
+		return sb.String()
+	case o.IgnoreTrailingSpace:
+		return strings.TrimRightFunc(s, unicode.IsSpace)
+	default:
+		return s
+	}
+}
+
+// DiffWithOptions returns the patience diff of two slices of strings,
+// comparing lines as specified by the options. The Text of Equal lines
+// is the original text of the source line.
+func DiffWithOptions(a, b []string, opts DiffOptions) []DiffLine {
+	return toDiffLines(DiffFunc(a, b, opts.normalize))
+}
@@ -0,0 +1,192 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDiffOptions_normalize(t *testing.T) {
+	tests := []struct {
+		name string
+		opts DiffOptions
+		s    string
+		want string
+	}{
+		{
+			name: "Test no options",
+			opts: DiffOptions{},
+			s:    " a \t b ",
+			want: " a \t b ",
+		},
+		{
+			name: "Test ignore all space",
+			opts: DiffOptions{IgnoreAllSpace: true},
+			s:    " a \t b ",
+			want: "ab",
+		},
+		{
+			name: "Test ignore space change",
+			opts: DiffOptions{IgnoreSpaceChange: true},
+			s:    " a \t b ",
+			want: " a b",
+		},
+		{
+			name: "Test ignore trailing space",
+			opts: DiffOptions{IgnoreTrailingSpace: true},
+			s:    " a \t b ",
+			want: " a \t b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.normalize(tt.s); got != tt.want {
+				t.Errorf("normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffWithOptions(t *testing.T) {
+	type args struct {
+		a    []string
+		b    []string
+		opts DiffOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want []DiffLine
+	}{
+		{
+			name: "Test empty slices",
+			args: args{
+				a:    []string{},
+				b:    []string{},
+				opts: DiffOptions{IgnoreSpaceChange: true},
+			},
+			want: nil,
+		},
+		{
+			name: "Test no options",
+			args: args{
+				a:    []string{"a", "b ", "c"},
+				b:    []string{"a", "b", "c"},
+				opts: DiffOptions{},
+			},
+			want: []DiffLine{
+				{Text: "a", Type: Equal, SrcLine: 1, DstLine: 1},
+				{Text: "b ", Type: Delete, SrcLine: 2},
+				{Text: "b", Type: Insert, DstLine: 2},
+				{Text: "c", Type: Equal, SrcLine: 3, DstLine: 3},
+			},
+		},
+		{
+			name: "Test ignore all space keeps the source text",
+			args: args{
+				a:    []string{"if (x) {", "\treturn"},
+				b:    []string{"if(x){", "    return", "}"},
+				opts: DiffOptions{IgnoreAllSpace: true},
+			},
+			want: []DiffLine{
+				{Text: "if (x) {", Type: Equal, SrcLine: 1, DstLine: 1},
+				{Text: "\treturn", Type: Equal, SrcLine: 2, DstLine: 2},
+				{Text: "}", Type: Insert, DstLine: 3},
+			},
+		},
+		{
+			name: "Test ignore space change",
+			args: args{
+				a:    []string{"a  b", "c d"},
+				b:    []string{"a b ", "cd"},
+				opts: DiffOptions{IgnoreSpaceChange: true},
+			},
+			want: []DiffLine{
+				{Text: "a  b", Type: Equal, SrcLine: 1, DstLine: 1},
+				{Text: "c d", Type: Delete, SrcLine: 2},
+				{Text: "cd", Type: Insert, DstLine: 2},
+			},
+		},
+		{
+			name: "Test ignore space change keeps leading space",
+			args: args{
+				a:    []string{"foo", "\tbar", "baz"},
+				b:    []string{"  foo", "  bar", "baz"},
+				opts: DiffOptions{IgnoreSpaceChange: true},
+			},
+			want: []DiffLine{
+				{Text: "foo", Type: Delete, SrcLine: 1},
+				{Text: "  foo", Type: Insert, DstLine: 1},
+				{Text: "\tbar", Type: Equal, SrcLine: 2, DstLine: 2},
+				{Text: "baz", Type: Equal, SrcLine: 3, DstLine: 3},
+			},
+		},
+		{
+			name: "Test ignore trailing space",
+			args: args{
+				a:    []string{"a ", " b"},
+				b:    []string{"a", "b"},
+				opts: DiffOptions{IgnoreTrailingSpace: true},
+			},
+			want: []DiffLine{
+				{Text: "a ", Type: Equal, SrcLine: 1, DstLine: 1},
+				{Text: " b", Type: Delete, SrcLine: 2},
+				{Text: "b", Type: Insert, DstLine: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffWithOptions(tt.args.a, tt.args.b, tt.args.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffWithOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDiffWithOptionsLineNumbers tests that the line numbers of a diff
+// count the source and destination lines under every option: Equal lines
+// have both, deletions only a source and insertions only a destination
+// line number.
+func TestDiffWithOptionsLineNumbers(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	opts := []DiffOptions{
+		{IgnoreAllSpace: true},
+		{IgnoreSpaceChange: true},
+		{IgnoreTrailingSpace: true},
+	}
+	spaces := []string{"", " ", "\t", "  "}
+	line := func() string {
+		return spaces[r.Intn(len(spaces))] + randomLines(r, 1, 3)[0] + spaces[r.Intn(len(spaces))]
+	}
+	for n := 0; n < 500; n++ {
+		a, b := make([]string, r.Intn(15)), make([]string, r.Intn(15))
+		for i := range a {
+			a[i] = line()
+		}
+		for i := range b {
+			b[i] = line()
+		}
+		for _, o := range opts {
+			src, dst := 0, 0
+			for _, l := range DiffWithOptions(a, b, o) {
+				wantSrc, wantDst := 0, 0
+				if l.Type != Insert {
+					src++
+					wantSrc = src
+				}
+				if l.Type != Delete {
+					dst++
+					wantDst = dst
+				}
+				if l.SrcLine != wantSrc || l.DstLine != wantDst {
+					t.Fatalf("DiffWithOptions(%q, %q, %+v) numbers %+v, want %d, %d", a, b, o, l, wantSrc, wantDst)
+				}
+			}
+			if src != len(a) || dst != len(b) {
+				t.Fatalf("DiffWithOptions(%q, %q, %+v) has %d, %d lines", a, b, o, src, dst)
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FilePatch represents the hunks of a unified diff for a single file.
+type FilePatch struct {
+	// SrcHeader is the header for the source file, without the "--- " prefix.
+	SrcHeader string
+	// DstHeader is the header for the destination file, without the "+++ " prefix.
+	DstHeader string
+	Hunks     []Hunk
+	// SrcNoNewline reports whether the source file has no newline at end of file.
+	SrcNoNewline bool
+	// DstNoNewline reports whether the destination file has no newline at end of file.
+	DstNoNewline bool
+}
+
+// ParseError represents an error parsing a unified diff.
+type ParseError struct {
+	// Line is the 1-based line number of the error.
+	Line int
+	Msg  string
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// hunkHeader matches a hunk header, capturing the line ranges and section.
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@(?: (.*))?$`)
+
+// parseHunkHeader returns a hunk with the line ranges and section of a
+// hunk header. Omitted line counts default to 1.
+func parseHunkHeader(line string) (Hunk, bool) {
+	m := hunkHeader.FindStringSubmatch(line)
+	if m == nil {
+		return Hunk{}, false
+	}
+	atoi := func(s string) int {
+		if len(s) == 0 {
+			return 1
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return -1
+		}
+		return n
+	}
+	h := Hunk{
+		SrcStart: atoi(m[1]),
+		SrcLines: atoi(m[2]),
+		DstStart: atoi(m[3]),
+		DstLines: atoi(m[4]),
+		Section:  m[5],
+	}
+	if h.SrcStart < 0 || h.SrcLines < 0 || h.DstStart < 0 || h.DstLines < 0 {
+		return Hunk{}, false
+	}
+	return h, true
+}
+
+// ParseUnifiedDiff parses a unified diff into the patches of each file.
+// Hunks that are not preceded by file headers are returned in a patch
+// with empty headers. Lines outside of headers and hunks are ignored.
+func ParseUnifiedDiff(r io.Reader) ([]FilePatch, error) {
+	patches := []FilePatch{}
+	br := bufio.NewReader(r)
+	lineNum := 0
+	srcLeft, dstLeft := 0, 0
+	var last DiffType
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if len(line) == 0 && errors.Is(err, io.EOF) {
+			break
+		}
+		lineNum++
+		line = strings.TrimSuffix(line, "\n")
+		cur := len(patches) - 1
+
+		switch {
+		case strings.HasPrefix(line, `\`):
+			// The previous line has no newline at end of file.
+			if cur < 0 || len(patches[cur].Hunks) == 0 || len(patches[cur].Hunks[len(patches[cur].Hunks)-1].Diffs) == 0 {
+				return nil, &ParseError{lineNum, "unexpected no newline marker"}
+			}
+			patches[cur].SrcNoNewline = patches[cur].SrcNoNewline || last != Insert
+			patches[cur].DstNoNewline = patches[cur].DstNoNewline || last != Delete
+
+		case srcLeft > 0 || dstLeft > 0:
+			// A line of the current hunk.
+			l := DiffLine{}
+			switch {
+			case len(line) == 0:
+				l.Type = Equal
+			case line[0] == ' ':
+				l = DiffLine{Text: line[1:], Type: Equal}
+			case line[0] == '-':
+				l = DiffLine{Text: line[1:], Type: Delete}
+			case line[0] == '+':
+				l = DiffLine{Text: line[1:], Type: Insert}
+			default:
+				return nil, &ParseError{lineNum, fmt.Sprintf("invalid hunk line %q", line)}
+			}
+			if l.Type != Insert {
+				srcLeft--
+			}
+			if l.Type != Delete {
+				dstLeft--
+			}
+			if srcLeft < 0 || dstLeft < 0 {
+				return nil, &ParseError{lineNum, "hunk has more lines than its header"}
+			}
+			h := &patches[cur].Hunks[len(patches[cur].Hunks)-1]
+			if l.Type != Insert {
+				l.SrcLine = h.SrcStart + h.SrcLines - srcLeft - 1
+			}
+			if l.Type != Delete {
+				l.DstLine = h.DstStart + h.DstLines - dstLeft - 1
+			}
+			h.Diffs = append(h.Diffs, l)
+			last = l.Type
+
+		case strings.HasPrefix(line, "--- "):
+			patches = append(patches, FilePatch{SrcHeader: line[4:]})
+
+		case strings.HasPrefix(line, "+++ "):
+			if cur < 0 || len(patches[cur].Hunks) > 0 || len(patches[cur].DstHeader) > 0 {
+				patches = append(patches, FilePatch{})
+				cur++
+			}
+			patches[cur].DstHeader = line[4:]
+
+		case strings.HasPrefix(line, "@@ "):
+			h, ok := parseHunkHeader(line)
+			if !ok {
+				return nil, &ParseError{lineNum, fmt.Sprintf("invalid hunk header %q", line)}
+			}
+			if cur < 0 {
+				patches = append(patches, FilePatch{})
+				cur++
+			}
+			patches[cur].Hunks = append(patches[cur].Hunks, h)
+			srcLeft, dstLeft = h.SrcLines, h.DstLines
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	// Empty context lines at the end of the input may have been stripped,
+	// so complete the last hunk if only context lines are missing.
+	if srcLeft > 0 && srcLeft == dstLeft {
+		cur := len(patches) - 1
+		h := &patches[cur].Hunks[len(patches[cur].Hunks)-1]
+		for ; srcLeft > 0; srcLeft, dstLeft = srcLeft-1, dstLeft-1 {
+			h.Diffs = append(h.Diffs, DiffLine{
+				Type:    Equal,
+				SrcLine: h.SrcStart + h.SrcLines - srcLeft,
+				DstLine: h.DstStart + h.DstLines - dstLeft,
+			})
+		}
+	}
+	if srcLeft > 0 || dstLeft > 0 {
+		return nil, &ParseError{lineNum, "unexpected end of hunk"}
+	}
+
+	return patches, nil
+}
@@ -0,0 +1,246 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parseHunkHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   Hunk
+		wantOk bool
+	}{
+		{
+			name:   "Test line ranges",
+			line:   "@@ -3,4 +5,6 @@",
+			want:   Hunk{SrcStart: 3, SrcLines: 4, DstStart: 5, DstLines: 6},
+			wantOk: true,
+		},
+		{
+			name:   "Test omitted line counts",
+			line:   "@@ -1 +1 @@",
+			want:   Hunk{SrcStart: 1, SrcLines: 1, DstStart: 1, DstLines: 1},
+			wantOk: true,
+		},
+		{
+			name:   "Test section text",
+			line:   "@@ -0,0 +1,2 @@ func main() {",
+			want:   Hunk{SrcStart: 0, SrcLines: 0, DstStart: 1, DstLines: 2, Section: "func main() {"},
+			wantOk: true,
+		},
+		{
+			name:   "Test invalid header",
+			line:   "@@ -a,b +c,d @@",
+			want:   Hunk{},
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOk := parseHunkHeader(tt.line)
+			if !reflect.DeepEqual(got, tt.want) || gotOk != tt.wantOk {
+				t.Errorf("parseHunkHeader() = %v, %v, want %v, %v", got, gotOk, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestParseUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want []FilePatch
+	}{
+		{
+			name: "Test empty diff",
+			diff: "",
+			want: []FilePatch{},
+		},
+		{
+			name: "Test hunks without headers",
+			diff: "@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+			want: []FilePatch{
+				{
+					Hunks: []Hunk{
+						{
+							Diffs: []DiffLine{
+								{Text: "a", Type: Equal, SrcLine: 1, DstLine: 1},
+								{Text: "b", Type: Delete, SrcLine: 2},
+								{Text: "c", Type: Insert, DstLine: 2},
+							},
+							SrcStart: 1,
+							SrcLines: 2,
+							DstStart: 1,
+							DstLines: 2,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Test multiple files with preamble",
+			diff: `diff --git a/x.txt b/x.txt
+index 0000000..1111111 100644
+--- a/x.txt
++++ b/x.txt
+@@ -1 +1 @@ section
+-x
++y
+@@ -5,2 +5,1 @@
+
+--- z
+--- a/y.txt	2021-01-01 00:00:00
++++ b/y.txt	2021-01-02 00:00:00
+@@ -1,0 +1 @@
++z
+`,
+			want: []FilePatch{
+				{
+					SrcHeader: "a/x.txt",
+					DstHeader: "b/x.txt",
+					Hunks: []Hunk{
+						{
+							Diffs: []DiffLine{
+								{Text: "x", Type: Delete, SrcLine: 1},
+								{Text: "y", Type: Insert, DstLine: 1},
+							},
+							SrcStart: 1,
+							SrcLines: 1,
+							DstStart: 1,
+							DstLines: 1,
+							Section:  "section",
+						},
+						{
+							Diffs: []DiffLine{
+								{Text: "", Type: Equal, SrcLine: 5, DstLine: 5},
+								{Text: "-- z", Type: Delete, SrcLine: 6},
+							},
+							SrcStart: 5,
+							SrcLines: 2,
+							DstStart: 5,
+							DstLines: 1,
+						},
+					},
+				},
+				{
+					SrcHeader: "a/y.txt\t2021-01-01 00:00:00",
+					DstHeader: "b/y.txt\t2021-01-02 00:00:00",
+					Hunks: []Hunk{
+						{
+							Diffs: []DiffLine{
+								{Text: "z", Type: Insert, DstLine: 1},
+							},
+							SrcStart: 1,
+							SrcLines: 0,
+							DstStart: 1,
+							DstLines: 1,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Test no newline at end of file",
+			diff: "--- a\n+++ b\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+b",
+			want: []FilePatch{
+				{
+					SrcHeader: "a",
+					DstHeader: "b",
+					Hunks: []Hunk{
+						{
+							Diffs: []DiffLine{
+								{Text: "a", Type: Delete, SrcLine: 1},
+								{Text: "b", Type: Insert, DstLine: 1},
+							},
+							SrcStart: 1,
+							SrcLines: 1,
+							DstStart: 1,
+							DstLines: 1,
+						},
+					},
+					SrcNoNewline: true,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUnifiedDiff(strings.NewReader(tt.diff))
+			if err != nil {
+				t.Fatalf("ParseUnifiedDiff() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUnifiedDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnifiedDiffErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		diff     string
+		wantLine int
+	}{
+		{
+			name:     "Test invalid hunk header",
+			diff:     "@@ -1,x +1 @@\n",
+			wantLine: 1,
+		},
+		{
+			name:     "Test invalid hunk line",
+			diff:     "@@ -1,2 +1,2 @@\n a\n*b\n",
+			wantLine: 3,
+		},
+		{
+			name:     "Test more lines than the header",
+			diff:     "@@ -1 +1,2 @@\n a\n-b\n",
+			wantLine: 3,
+		},
+		{
+			name:     "Test unexpected end of hunk",
+			diff:     "@@ -1,3 +1,2 @@\n a\n",
+			wantLine: 2,
+		},
+		{
+			name:     "Test unexpected no newline marker",
+			diff:     "\\ No newline at end of file\n",
+			wantLine: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseUnifiedDiff(strings.NewReader(tt.diff))
+			var perr *ParseError
+			if !errors.As(err, &perr) || perr.Line != tt.wantLine {
+				t.Errorf("ParseUnifiedDiff() error = %v, want line %d", err, tt.wantLine)
+			}
+		})
+	}
+}
+
+// TestParseUnifiedDiffRoundTrip tests parsing the output of UnifiedDiffTextWithOptions,
+// including empty context lines at the end, which are formatted as empty text
+// without a final newline.
+func TestParseUnifiedDiffRoundTrip(t *testing.T) {
+	a := strings.Split("the\nquick\nbrown\nchicken\njumps\nover\nthe\n\ndog\n\n", "\n")
+	b := strings.Split("the\nquick\nbrown\nfox\njumps\nover\nthe\n\nlazy\ndog\n\n", "\n")
+	diffs := Diff(a, b)
+	text := UnifiedDiffTextWithOptions(
+		diffs,
+		UnifiedDiffOptions{Precontext: 1, Postcontext: 3, SrcHeader: "a.txt", DstHeader: "b.txt"},
+	)
+	got, err := ParseUnifiedDiff(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("ParseUnifiedDiff() error = %v", err)
+	}
+	want := []FilePatch{{SrcHeader: "a.txt", DstHeader: "b.txt", Hunks: Hunks(diffs, 1, 3)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseUnifiedDiff() = %v, want %v", got, want)
+	}
+}
@@ -1,6 +1,8 @@
 // Package patience implements the Patience Diff algorithm.
 package patience
 
+import "context"
+
 // DiffType defines the type of a diff element.
 type DiffType int8
 
@@ -17,99 +19,274 @@ const (
 type DiffLine struct {
 	Text string
 	Type DiffType
+	// SrcLine is the 1-based line number in the source, or 0 for insertions.
+	SrcLine int
+	// DstLine is the 1-based line number in the destination, or 0 for deletions.
+	DstLine int
+	// Segments optionally divides the text of a changed line into the
+	// parts that are unchanged (Equal) and changed (Delete or Insert).
+	Segments []Segment
+	// Move optionally identifies a block of lines that was moved. Deleted
+	// and inserted lines of the same block have the same non-zero Move.
+	Move int
+}
+
+// Edit represents a single element of any type and its diff type.
+type Edit[T any] struct {
+	Value T
+	Type  DiffType
 }
 
-// toDiffLines is a convenience function to convert a slice of strings
+// appendDiffLines is a convenience function to append a slice of strings
 // to a slice of DiffLines with the specified diff type.
-func toDiffLines(a []string, t DiffType) []DiffLine {
-	diffs := make([]DiffLine, len(a))
-	for i, l := range a {
-		diffs[i] = DiffLine{l, t}
+func appendDiffLines(diffs []DiffLine, a []string, t DiffType) []DiffLine {
+	for _, l := range a {
+		diffs = append(diffs, DiffLine{Text: l, Type: t})
+	}
+	return diffs
+}
+
+// appendEdits is a convenience function to append a slice of elements
+// to a slice of Edits with the specified diff type.
+func appendEdits[T any](edits []Edit[T], a []T, t DiffType) []Edit[T] {
+	for _, e := range a {
+		edits = append(edits, Edit[T]{e, t})
+	}
+	return edits
+}
+
+// toEdits is a convenience function to convert a slice of elements
+// to a slice of Edits with the specified diff type.
+func toEdits[T any](a []T, t DiffType) []Edit[T] {
+	return appendEdits(make([]Edit[T], 0, len(a)), a, t)
+}
+
+// numberLines sets the source and destination line numbers of a diff.
+func numberLines(diffs []DiffLine) {
+	src, dst := 0, 0
+	for i, l := range diffs {
+		diffs[i].SrcLine, diffs[i].DstLine = 0, 0
+		if l.Type != Insert {
+			src++
+			diffs[i].SrcLine = src
+		}
+		if l.Type != Delete {
+			dst++
+			diffs[i].DstLine = dst
+		}
+	}
+}
+
+// toDiffLines converts a slice of string Edits to a slice of numbered DiffLines.
+func toDiffLines(edits []Edit[string]) []DiffLine {
+	if edits == nil {
+		return nil
+	}
+	diffs := make([]DiffLine, len(edits))
+	for i, e := range edits {
+		diffs[i] = DiffLine{Text: e.Value, Type: e.Type}
 	}
+	numberLines(diffs)
 	return diffs
 }
 
+// keys returns the keys of a slice of elements.
+func keys[T any, K comparable](a []T, key func(T) K) []K {
+	ks := make([]K, len(a))
+	for i, e := range a {
+		ks[i] = key(e)
+	}
+	return ks
+}
+
+// intern maps the elements of slices a and b to integer IDs, such that
+// equal elements have equal IDs. It returns the IDs of the elements of
+// each slice, and the number of distinct elements.
+func intern[K comparable](a, b []K) ([]int, []int, int) {
+	ids := make(map[K]int)
+	internSlice := func(s []K) []int {
+		is := make([]int, len(s))
+		for i, e := range s {
+			id, ok := ids[e]
+			if !ok {
+				id = len(ids)
+				ids[e] = id
+			}
+			is[i] = id
+		}
+		return is
+	}
+	ia := internSlice(a)
+	ib := internSlice(b)
+	return ia, ib, len(ids)
+}
+
 // uniqueElements returns a slice of unique elements from a slice of
-// strings, and a slice of the original indices of each element.
-func uniqueElements(a []string) ([]string, []int) {
-	m := make(map[string]int)
+// element IDs, and a slice of the original indices of each element.
+// The counts slice must be indexable by every ID and contain only zeros.
+// It is left in the same state on return.
+func uniqueElements(a, counts []int) ([]int, []int) {
 	for _, e := range a {
-		m[e]++
+		counts[e]++
 	}
-	elements := []string{}
+	elements := []int{}
 	indices := []int{}
 	for i, e := range a {
-		if m[e] == 1 {
+		if counts[e] == 1 {
 			elements = append(elements, e)
 			indices = append(indices, i)
 		}
 	}
+	for _, e := range a {
+		counts[e] = 0
+	}
 	return elements, indices
 }
 
-// Diff returns the patience diff of two slices of strings.
-func Diff(a, b []string) []DiffLine {
-	switch {
-	case len(a) == 0 && len(b) == 0:
+// done returns the error of a context if it is done, without blocking.
+func done(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
 		return nil
-	case len(a) == 0:
-		return toDiffLines(b, Insert)
-	case len(b) == 0:
-		return toDiffLines(a, Delete)
 	}
+}
 
-	// Find equal elements at the head of slices a and b.
-	i := 0
-	for i < len(a) && i < len(b) && a[i] == b[i] {
-		i++
-	}
-	if i > 0 {
-		return append(
-			toDiffLines(a[:i], Equal),
-			Diff(a[i:], b[i:])...,
-		)
-	}
+// region represents a pending step of a patience diff. It is either a
+// region of slices a and b to diff, or a run of equal elements to append.
+type region struct {
+	aLo, aHi int
+	bLo, bHi int
+	equal    bool
+}
 
-	// Find equal elements at the tail of slices a and b.
-	j := 0
-	for j < len(a) && j < len(b) && a[len(a)-1-j] == b[len(b)-1-j] {
-		j++
+// patienceDiff returns the patience diff of two slices of elements,
+// comparing the element IDs of each slice. The IDs must be less than n.
+// An error is returned if the context is done before the diff is complete.
+func patienceDiff[T any](ctx context.Context, a, b []T, ia, ib []int, n int) ([]Edit[T], error) {
+	if len(a) == 0 && len(b) == 0 {
+		return nil, nil
 	}
-	if j > 0 {
-		return append(
-			Diff(a[:len(a)-j], b[:len(b)-j]),
-			toDiffLines(a[len(a)-j:], Equal)...,
-		)
+
+	counts := make([]int, n)
+	positions := make([]int, n)
+	for i := range positions {
+		positions[i] = -1
 	}
 
-	// Find the longest common subsequence of unique elements in a and b.
-	ua, idxa := uniqueElements(a)
-	ub, idxb := uniqueElements(b)
-	lcs := LCS(ua, ub)
+	// Diff regions from an explicit stack, appending to a single buffer.
+	// Regions are pushed in reverse order so that they are popped in order.
+	diffs := make([]Edit[T], 0, len(a)+len(b))
+	stack := []region{{aHi: len(a), bHi: len(b)}}
+	for len(stack) > 0 {
+		if err := done(ctx); err != nil {
+			return nil, err
+		}
 
-	// If the LCS is empty, the diff is all deletions and insertions.
-	if len(lcs) == 0 {
-		return append(toDiffLines(a, Delete), toDiffLines(b, Insert)...)
-	}
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if r.equal {
+			diffs = appendEdits(diffs, a[r.aLo:r.aHi], Equal)
+			continue
+		}
 
-	// Lookup the original indices of slices a and b.
-	for i, x := range lcs {
-		lcs[i][0] = idxa[x[0]]
-		lcs[i][1] = idxb[x[1]]
-	}
+		// Find equal elements at the head of the region.
+		head := r.aLo
+		for r.aLo < r.aHi && r.bLo < r.bHi && ia[r.aLo] == ib[r.bLo] {
+			r.aLo++
+			r.bLo++
+		}
+		diffs = appendEdits(diffs, a[head:r.aLo], Equal)
+
+		// Find equal elements at the tail of the region.
+		tail := r.aHi
+		for r.aLo < r.aHi && r.bLo < r.bHi && ia[r.aHi-1] == ib[r.bHi-1] {
+			r.aHi--
+			r.bHi--
+		}
+		if r.aHi < tail {
+			stack = append(stack, region{aLo: r.aHi, aHi: tail, equal: true})
+		}
+
+		switch {
+		case r.aLo == r.aHi:
+			diffs = appendEdits(diffs, b[r.bLo:r.bHi], Insert)
+			continue
+		case r.bLo == r.bHi:
+			diffs = appendEdits(diffs, a[r.aLo:r.aHi], Delete)
+			continue
+		}
+
+		// Find the longest common subsequence of unique elements in the region.
+		ua, idxa := uniqueElements(ia[r.aLo:r.aHi], counts)
+		ub, idxb := uniqueElements(ib[r.bLo:r.bHi], counts)
+		lcs := patienceLCS(ua, ub, positions)
 
-	diffs := []DiffLine{}
-	ga, gb := 0, 0
-	for _, ip := range lcs {
-		// Diff the gaps between the lcs elements.
-		diffs = append(diffs, Diff(a[ga:ip[0]], b[gb:ip[1]])...)
-		// Append the LCS elements to the diff.
-		diffs = append(diffs, DiffLine{Type: Equal, Text: a[ip[0]]})
-		ga = ip[0] + 1
-		gb = ip[1] + 1
+		// If the LCS is empty there are no anchors, so fall back to a minimal
+		// diff of all elements.
+		if len(lcs) == 0 {
+			var err error
+			diffs, err = myersDiff(
+				ctx,
+				diffs,
+				a[r.aLo:r.aHi], b[r.bLo:r.bHi],
+				ia[r.aLo:r.aHi], ib[r.bLo:r.bHi],
+			)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Push the gaps between the LCS elements, and the LCS elements
+		// themselves, using the original indices of slices a and b.
+		ga, gb := r.aHi, r.bHi
+		for k := len(lcs) - 1; k >= 0; k-- {
+			ia, ib := r.aLo+idxa[lcs[k][0]], r.bLo+idxb[lcs[k][1]]
+			if ia+1 < ga || ib+1 < gb {
+				stack = append(stack, region{aLo: ia + 1, aHi: ga, bLo: ib + 1, bHi: gb})
+			}
+			stack = append(stack, region{aLo: ia, aHi: ia + 1, equal: true})
+			ga, gb = ia, ib
+		}
+		if r.aLo < ga || r.bLo < gb {
+			stack = append(stack, region{aLo: r.aLo, aHi: ga, bLo: r.bLo, bHi: gb})
+		}
 	}
-	// Diff the remaining elements of a and b after the final LCS element.
-	diffs = append(diffs, Diff(a[ga:], b[gb:])...)
 
-	return diffs
+	return diffs, nil
+}
+
+// DiffSlices returns the patience diff of two slices of comparable elements.
+func DiffSlices[T comparable](a, b []T) []Edit[T] {
+	ia, ib, n := intern(a, b)
+	edits, _ := patienceDiff(context.Background(), a, b, ia, ib, n)
+	return edits
+}
+
+// DiffFunc returns the patience diff of two slices of elements of any type.
+// Elements are equal if the key function returns equal keys for them,
+// and the Values of Equal edits are the elements of slice a.
+func DiffFunc[T any, K comparable](a, b []T, key func(T) K) []Edit[T] {
+	ia, ib, n := intern(keys(a, key), keys(b, key))
+	edits, _ := patienceDiff(context.Background(), a, b, ia, ib, n)
+	return edits
+}
+
+// Diff returns the patience diff of two slices of strings.
+func Diff(a, b []string) []DiffLine {
+	return toDiffLines(DiffSlices(a, b))
+}
+
+// DiffContext returns the patience diff of two slices of strings.
+// If the context is done before the diff is complete, the context's
+// error is returned.
+func DiffContext(ctx context.Context, a, b []string) ([]DiffLine, error) {
+	ia, ib, n := intern(a, b)
+	edits, err := patienceDiff(ctx, a, b, ia, ib, n)
+	if err != nil {
+		return nil, err
+	}
+	return toDiffLines(edits), nil
 }
@@ -2,48 +2,70 @@
 package patience
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
 	"testing"
+	"time"
 )
 
+func Test_intern(t *testing.T) {
+	gotA, gotB, gotN := intern([]string{"a", "b", "a"}, []string{"c", "b", "a"})
+	if want := []int{0, 1, 0}; !reflect.DeepEqual(gotA, want) {
+		t.Errorf("intern() = %v, want %v", gotA, want)
+	}
+	if want := []int{2, 1, 0}; !reflect.DeepEqual(gotB, want) {
+		t.Errorf("intern() = %v, want %v", gotB, want)
+	}
+	if gotN != 3 {
+		t.Errorf("intern() = %v, want %v", gotN, 3)
+	}
+}
+
 func Test_uniqueElements(t *testing.T) {
 	type args struct {
-		a []string
+		a []int
 	}
 	tests := []struct {
 		name        string
 		args        args
-		wantOut     []string
+		wantOut     []int
 		wantIndices []int
 	}{
 		{
 			name: "Test every element is unique",
 			args: args{
-				a: []string{"a", "b", "c"},
+				a: []int{0, 1, 2},
 			},
-			wantOut:     []string{"a", "b", "c"},
+			wantOut:     []int{0, 1, 2},
 			wantIndices: []int{0, 1, 2},
 		},
 		{
 			name: "Test duplicate elements",
 			args: args{
-				a: []string{"a", "b", "a", "c"},
+				a: []int{0, 1, 0, 2},
 			},
-			wantOut:     []string{"b", "c"},
+			wantOut:     []int{1, 2},
 			wantIndices: []int{1, 3},
 		},
 		{
 			name: "Test no unique elements",
 			args: args{
-				a: []string{"a", "b", "a", "c", "c", "b"},
+				a: []int{0, 1, 0, 2, 2, 1},
 			},
-			wantOut:     []string{},
+			wantOut:     []int{},
 			wantIndices: []int{},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotOut, gotIndices := uniqueElements(tt.args.a)
+			counts := make([]int, 3)
+			gotOut, gotIndices := uniqueElements(tt.args.a, counts)
+			if !reflect.DeepEqual(counts, []int{0, 0, 0}) {
+				t.Errorf("uniqueElements() left counts %v", counts)
+			}
 			if !reflect.DeepEqual(gotOut, tt.wantOut) {
 				t.Errorf("uniqueElements() = %v, want %v", gotOut, tt.wantOut)
 			}
@@ -79,7 +101,7 @@ func TestDiff(t *testing.T) {
 				b: []string{"a"},
 			},
 			want: []DiffLine{
-				{Text: "a", Type: Insert},
+				{Text: "a", Type: Insert, DstLine: 1},
 			},
 		},
 		{
@@ -89,7 +111,7 @@ func TestDiff(t *testing.T) {
 				b: []string{},
 			},
 			want: []DiffLine{
-				{Text: "a", Type: Delete},
+				{Text: "a", Type: Delete, SrcLine: 1},
 			},
 		},
 		{
@@ -99,7 +121,7 @@ func TestDiff(t *testing.T) {
 				b: []string{"a"},
 			},
 			want: []DiffLine{
-				{Text: "a", Type: Equal},
+				{Text: "a", Type: Equal, SrcLine: 1, DstLine: 1},
 			},
 		},
 		{
@@ -109,9 +131,9 @@ func TestDiff(t *testing.T) {
 				b: []string{"a", "c"},
 			},
 			want: []DiffLine{
-				{Text: "a", Type: Equal},
-				{Text: "b", Type: Delete},
-				{Text: "c", Type: Insert},
+				{Text: "a", Type: Equal, SrcLine: 1, DstLine: 1},
+				{Text: "b", Type: Delete, SrcLine: 2},
+				{Text: "c", Type: Insert, DstLine: 2},
 			},
 		},
 		{
@@ -121,9 +143,9 @@ func TestDiff(t *testing.T) {
 				b: []string{"b", "c"},
 			},
 			want: []DiffLine{
-				{Text: "a", Type: Delete},
-				{Text: "b", Type: Insert},
-				{Text: "c", Type: Equal},
+				{Text: "a", Type: Delete, SrcLine: 1},
+				{Text: "b", Type: Insert, DstLine: 1},
+				{Text: "c", Type: Equal, SrcLine: 2, DstLine: 2},
 			},
 		},
 		{
@@ -133,10 +155,10 @@ func TestDiff(t *testing.T) {
 				b: []string{"a", "d", "c"},
 			},
 			want: []DiffLine{
-				{Text: "a", Type: Equal},
-				{Text: "b", Type: Delete},
-				{Text: "d", Type: Insert},
-				{Text: "c", Type: Equal},
+				{Text: "a", Type: Equal, SrcLine: 1, DstLine: 1},
+				{Text: "b", Type: Delete, SrcLine: 2},
+				{Text: "d", Type: Insert, DstLine: 2},
+				{Text: "c", Type: Equal, SrcLine: 3, DstLine: 3},
 			},
 		},
 		{
@@ -146,13 +168,41 @@ func TestDiff(t *testing.T) {
 				b: []string{"a", "y", "b", "z", "c"},
 			},
 			want: []DiffLine{
-				{Text: "a", Type: Equal},
-				{Text: "w", Type: Delete},
-				{Text: "y", Type: Insert},
-				{Text: "b", Type: Equal},
-				{Text: "x", Type: Delete},
-				{Text: "z", Type: Insert},
-				{Text: "c", Type: Equal},
+				{Text: "a", Type: Equal, SrcLine: 1, DstLine: 1},
+				{Text: "w", Type: Delete, SrcLine: 2},
+				{Text: "y", Type: Insert, DstLine: 2},
+				{Text: "b", Type: Equal, SrcLine: 3, DstLine: 3},
+				{Text: "x", Type: Delete, SrcLine: 4},
+				{Text: "z", Type: Insert, DstLine: 4},
+				{Text: "c", Type: Equal, SrcLine: 5, DstLine: 5},
+			},
+		},
+		{
+			name: "Test falling back when there are no unique common elements",
+			args: args{
+				a: []string{"a", "a", "x"},
+				b: []string{"y", "a", "a"},
+			},
+			want: []DiffLine{
+				{Text: "y", Type: Insert, DstLine: 1},
+				{Text: "a", Type: Equal, SrcLine: 1, DstLine: 2},
+				{Text: "a", Type: Equal, SrcLine: 2, DstLine: 3},
+				{Text: "x", Type: Delete, SrcLine: 3},
+			},
+		},
+		{
+			name: "Test deletions precede insertions in fallback regions",
+			args: args{
+				a: []string{"lA", "lB", "lC"},
+				b: []string{"chgc", "lB", "chgb", "newa"},
+			},
+			want: []DiffLine{
+				{Text: "lA", Type: Delete, SrcLine: 1},
+				{Text: "chgc", Type: Insert, DstLine: 1},
+				{Text: "lB", Type: Equal, SrcLine: 2, DstLine: 2},
+				{Text: "lC", Type: Delete, SrcLine: 3},
+				{Text: "chgb", Type: Insert, DstLine: 3},
+				{Text: "newa", Type: Insert, DstLine: 4},
 			},
 		},
 	}
@@ -164,3 +214,149 @@ func TestDiff(t *testing.T) {
 		})
 	}
 }
+
+// benchmarkLines returns source and destination slices of n lines where
+// every tenth line of the destination is modified, and every hundredth
+// line is a non-unique brace.
+func benchmarkLines(n int) ([]string, []string) {
+	a := make([]string, n)
+	b := make([]string, n)
+	for i := range a {
+		a[i] = fmt.Sprintf("line %d", i)
+		b[i] = a[i]
+		switch {
+		case i%100 == 0:
+			a[i], b[i] = "}", "}"
+		case i%10 == 0:
+			b[i] = fmt.Sprintf("modified line %d", i)
+		}
+	}
+	return a, b
+}
+
+func BenchmarkDiff(b *testing.B) {
+	for _, n := range []int{1000, 10000, 100000} {
+		src, dst := benchmarkLines(n)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Diff(src, dst)
+			}
+		})
+	}
+}
+
+func BenchmarkHistogramDiff(b *testing.B) {
+	for _, n := range []int{1000, 10000, 100000} {
+		src, dst := benchmarkLines(n)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				HistogramDiff(src, dst)
+			}
+		})
+	}
+}
+
+func BenchmarkDiffNoUniqueLines(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		src, dst := make([]string, n), make([]string, n)
+		for i := range src {
+			src[i] = strconv.Itoa(i % 2)
+			dst[i] = strconv.Itoa(i % 3 % 2)
+		}
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Diff(src, dst)
+			}
+		})
+	}
+}
+
+func TestDiffSlices(t *testing.T) {
+	got := DiffSlices([]int{1, 2, 3, 4}, []int{1, 3, 5, 4})
+	want := []Edit[int]{
+		{Value: 1, Type: Equal},
+		{Value: 2, Type: Delete},
+		{Value: 3, Type: Equal},
+		{Value: 5, Type: Insert},
+		{Value: 4, Type: Equal},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffSlices() = %v, want %v", got, want)
+	}
+	if got := DiffSlices([]int{}, []int{}); got != nil {
+		t.Errorf("DiffSlices() = %v, want %v", got, nil)
+	}
+}
+
+func TestDiffFunc(t *testing.T) {
+	type record struct {
+		ID    int
+		Value []string
+	}
+	a := []record{{1, []string{"a"}}, {2, []string{"b"}}, {3, []string{"c"}}}
+	b := []record{{1, []string{"x"}}, {3, []string{"y"}}, {4, []string{"z"}}}
+	got := DiffFunc(a, b, func(r record) int { return r.ID })
+	want := []Edit[record]{
+		{Value: a[0], Type: Equal},
+		{Value: a[1], Type: Delete},
+		{Value: a[2], Type: Equal},
+		{Value: b[2], Type: Insert},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffFunc() = %v, want %v", got, want)
+	}
+}
+
+func TestDiffContext(t *testing.T) {
+	a := []string{"a", "w", "b", "x", "c"}
+	b := []string{"a", "y", "b", "z", "c"}
+	got, err := DiffContext(context.Background(), a, b)
+	if err != nil {
+		t.Fatalf("DiffContext() error = %v", err)
+	}
+	if want := Diff(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffContext() = %v, want %v", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := DiffContext(ctx, a, b); !errors.Is(err, context.Canceled) {
+		t.Errorf("DiffContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+// TestDiffContextDeadline tests that a diff of a region without unique
+// elements stops when the deadline is exceeded.
+func TestDiffContextDeadline(t *testing.T) {
+	a, b := make([]string, 50000), make([]string, 50000)
+	for i := range a {
+		a[i] = strconv.Itoa(i % 2)
+		b[i] = strconv.Itoa(i % 3 % 2)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := DiffContext(ctx, a, b); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("DiffContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func Test_numberLines(t *testing.T) {
+	diffs := []DiffLine{
+		{Text: "a", Type: Insert},
+		{Text: "b", Type: Equal},
+		{Text: "c", Type: Delete},
+		{Text: "d", Type: Equal},
+	}
+	want := []DiffLine{
+		{Text: "a", Type: Insert, DstLine: 1},
+		{Text: "b", Type: Equal, SrcLine: 1, DstLine: 2},
+		{Text: "c", Type: Delete, SrcLine: 2},
+		{Text: "d", Type: Equal, SrcLine: 3, DstLine: 3},
+	}
+	if numberLines(diffs); !reflect.DeepEqual(diffs, want) {
+		t.Errorf("numberLines() = %v, want %v", diffs, want)
+	}
+}
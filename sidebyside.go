@@ -0,0 +1,238 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// defaultSideBySideWidth is the default total width of side-by-side text.
+	defaultSideBySideWidth = 130
+	// defaultTabSize is the default number of columns between tab stops.
+	defaultTabSize = 8
+)
+
+// SideBySideOptions represents the options for SideBySideTextWithOptions.
+type SideBySideOptions struct {
+	// Width is the total width of each row. It defaults to 130. Rows are
+	// wider when it leaves less than one column for the text of each line.
+	Width int
+	// Separator surrounds the marker between the columns. It defaults to a space.
+	Separator string
+	// ChangeMarker marks a row with a changed line. It defaults to "|".
+	ChangeMarker string
+	// DeleteMarker marks a row with a deleted line. It defaults to "<".
+	DeleteMarker string
+	// InsertMarker marks a row with an inserted line. It defaults to ">".
+	InsertMarker string
+	// LineNumbers prefixes each line with its line number.
+	LineNumbers bool
+	// TabSize is the number of columns between tab stops. It defaults to 8.
+	TabSize int
+	// Wrap wraps long lines onto continuation rows instead of truncating them.
+	Wrap bool
+	// SuppressCommonLines omits rows of equal lines.
+	SuppressCommonLines bool
+}
+
+// sideBySideRow represents a row of side-by-side text. A nil line is an
+// empty side of the row.
+type sideBySideRow struct {
+	src, dst *DiffLine
+	marker   string
+}
+
+// sideBySideRows returns the rows of a diff. Within each block of adjacent
+// deletions and insertions, the nth deleted line is paired with the nth
+// inserted line on a changed row.
+func sideBySideRows(diffs []DiffLine, opts SideBySideOptions) []sideBySideRow {
+	rows := []sideBySideRow{}
+	for i := 0; i < len(diffs); {
+		if diffs[i].Type == Equal {
+			if !opts.SuppressCommonLines {
+				rows = append(rows, sideBySideRow{src: &diffs[i], dst: &diffs[i]})
+			}
+			i++
+			continue
+		}
+
+		// Collect the deletions and insertions of the block of changes.
+		dels, inss := []int{}, []int{}
+		for ; i < len(diffs) && diffs[i].Type != Equal; i++ {
+			if diffs[i].Type == Delete {
+				dels = append(dels, i)
+			} else {
+				inss = append(inss, i)
+			}
+		}
+
+		for k := 0; k < len(dels) || k < len(inss); k++ {
+			switch {
+			case k >= len(inss):
+				rows = append(rows, sideBySideRow{src: &diffs[dels[k]], marker: opts.DeleteMarker})
+			case k >= len(dels):
+				rows = append(rows, sideBySideRow{dst: &diffs[inss[k]], marker: opts.InsertMarker})
+			default:
+				rows = append(rows, sideBySideRow{
+					src:    &diffs[dels[k]],
+					dst:    &diffs[inss[k]],
+					marker: opts.ChangeMarker,
+				})
+			}
+		}
+	}
+	return rows
+}
+
+// expandTabs returns a string with tabs replaced by spaces up to the next tab stop.
+func expandTabs(s string, tabSize int) string {
+	if !strings.Contains(s, "\t") {
+		return s
+	}
+	var sb strings.Builder
+	col := 0
+	for _, r := range s {
+		if r == '\t' {
+			n := tabSize - col%tabSize
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		sb.WriteRune(r)
+		col++
+	}
+	return sb.String()
+}
+
+// splitColumns splits a string into chunks of at most width runes. If wrap
+// is false, only the first chunk is returned.
+func splitColumns(s string, width int, wrap bool) []string {
+	chunks := []string{}
+	for {
+		n, i := 0, 0
+		for i < len(s) && n < width {
+			_, size := utf8.DecodeRuneInString(s[i:])
+			i += size
+			n++
+		}
+		chunks = append(chunks, s[:i])
+		s = s[i:]
+		if !wrap || len(s) == 0 || width <= 0 {
+			return chunks
+		}
+	}
+}
+
+// padColumn returns a string padded with spaces to a width in runes.
+func padColumn(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// SideBySideTextWithOptions returns the diff text in side-by-side format,
+// with the source lines in the left column and the destination lines in the
+// right column, like diff -y.
+func SideBySideTextWithOptions(diffs []DiffLine, opts SideBySideOptions) string {
+	if opts.Width <= 0 {
+		opts.Width = defaultSideBySideWidth
+	}
+	if len(opts.Separator) == 0 {
+		opts.Separator = " "
+	}
+	if len(opts.ChangeMarker) == 0 {
+		opts.ChangeMarker = "|"
+	}
+	if len(opts.DeleteMarker) == 0 {
+		opts.DeleteMarker = "<"
+	}
+	if len(opts.InsertMarker) == 0 {
+		opts.InsertMarker = ">"
+	}
+	if opts.TabSize <= 0 {
+		opts.TabSize = defaultTabSize
+	}
+
+	markerWidth := max(
+		utf8.RuneCountInString(opts.ChangeMarker),
+		max(utf8.RuneCountInString(opts.DeleteMarker), utf8.RuneCountInString(opts.InsertMarker)),
+	)
+	columnWidth := (opts.Width - markerWidth - 2*utf8.RuneCountInString(opts.Separator)) / 2
+
+	// Reserve space in each column for the line numbers.
+	numberWidth := 0
+	if opts.LineNumbers {
+		for _, l := range diffs {
+			numberWidth = max(numberWidth, len(strconv.Itoa(max(l.SrcLine, l.DstLine))))
+		}
+	}
+	gutterWidth := 0
+	if opts.LineNumbers {
+		gutterWidth = numberWidth + 1
+	}
+	textWidth := max(columnWidth-gutterWidth, 1)
+	columnWidth = textWidth + gutterWidth
+
+	// column returns the lines of a column, with the line number on the
+	// first line only.
+	column := func(l *DiffLine, number int) []string {
+		if l == nil {
+			return []string{""}
+		}
+		chunks := splitColumns(expandTabs(l.Text, opts.TabSize), textWidth, opts.Wrap)
+		if opts.LineNumbers {
+			for k := range chunks {
+				if k == 0 && number > 0 {
+					chunks[k] = fmt.Sprintf("%*d %s", numberWidth, number, chunks[k])
+				} else {
+					chunks[k] = strings.Repeat(" ", numberWidth+1) + chunks[k]
+				}
+			}
+		}
+		return chunks
+	}
+
+	s := []string{}
+	for _, r := range sideBySideRows(diffs, opts) {
+		var src, dst []string
+		if r.src != nil {
+			src = column(r.src, r.src.SrcLine)
+		} else {
+			src = column(nil, 0)
+		}
+		if r.dst != nil {
+			dst = column(r.dst, r.dst.DstLine)
+		} else {
+			dst = column(nil, 0)
+		}
+		for k := 0; k < len(src) || k < len(dst); k++ {
+			left, right, marker := "", "", ""
+			if k < len(src) {
+				left = src[k]
+			}
+			if k < len(dst) {
+				right = dst[k]
+			}
+			if k == 0 {
+				marker = r.marker
+			}
+			row := padColumn(left, columnWidth) +
+				opts.Separator + padColumn(marker, markerWidth) + opts.Separator
+			if len(right) == 0 {
+				row = strings.TrimRight(row, " ")
+			}
+			s = append(s, row+right)
+		}
+	}
+	return strings.Join(s, "\n")
+}
+
+// SideBySideText returns the diff text in side-by-side format with a width
+// of 130 columns.
+func SideBySideText(diffs []DiffLine) string {
+	return SideBySideTextWithOptions(diffs, SideBySideOptions{})
+}
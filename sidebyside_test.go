@@ -0,0 +1,124 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSideBySideTextWithOptions(t *testing.T) {
+	a := strings.Split("the\nquick\nbrown\nchicken\njumps\tover\nthe\ndog", "\n")
+	b := strings.Split("the\nquick\nbrown\nfox\njumps\tover\nthe\nlazy\ndog\nend", "\n")
+
+	type args struct {
+		diffs []DiffLine
+		opts  SideBySideOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Empty",
+			args: args{
+				diffs: nil,
+				opts:  SideBySideOptions{},
+			},
+			want: "",
+		},
+		{
+			name: "Width",
+			args: args{
+				diffs: Diff(a, b),
+				opts:  SideBySideOptions{Width: 40},
+			},
+			want: "the                  the\n" +
+				"quick                quick\n" +
+				"brown                brown\n" +
+				"chicken            | fox\n" +
+				"jumps   over         jumps   over\n" +
+				"the                  the\n" +
+				"                   > lazy\n" +
+				"dog                  dog\n" +
+				"                   > end",
+		},
+		{
+			name: "Deleted lines",
+			args: args{
+				diffs: Diff([]string{"a", "b", "c"}, []string{"x"}),
+				opts:  SideBySideOptions{Width: 11},
+			},
+			want: "a    | x\n" +
+				"b    <\n" +
+				"c    <",
+		},
+		{
+			name: "Separator, markers and tab size",
+			args: args{
+				diffs: Diff([]string{"a\tb", "c"}, []string{"a\tb", "d"}),
+				opts: SideBySideOptions{
+					Width:        21,
+					Separator:    " : ",
+					ChangeMarker: "!!",
+					TabSize:      2,
+				},
+			},
+			want: "a b    :    : a b\n" +
+				"c      : !! : d",
+		},
+		{
+			name: "Line numbers and suppressed common lines",
+			args: args{
+				diffs: Diff(a, b),
+				opts:  SideBySideOptions{Width: 30, LineNumbers: true, SuppressCommonLines: true},
+			},
+			want: "4 chicken     | 4 fox\n" +
+				"              > 7 lazy\n" +
+				"              > 9 end",
+		},
+		{
+			name: "Truncated lines",
+			args: args{
+				diffs: Diff([]string{"abcdefghijklmnop"}, []string{"x"}),
+				opts:  SideBySideOptions{Width: 21, LineNumbers: true},
+			},
+			want: "1 abcdefg | 1 x",
+		},
+		{
+			name: "Wrapped lines",
+			args: args{
+				diffs: Diff([]string{"abcdefghijklmnop"}, []string{"x"}),
+				opts:  SideBySideOptions{Width: 21, LineNumbers: true, Wrap: true},
+			},
+			want: "1 abcdefg | 1 x\n" +
+				"  hijklmn\n" +
+				"  op",
+		},
+		{
+			name: "Wrapped multibyte lines",
+			args: args{
+				diffs: Diff([]string{"ab"}, []string{"äöüß"}),
+				opts:  SideBySideOptions{Width: 7, Wrap: true},
+			},
+			want: "ab | äö\n" +
+				"     üß",
+		},
+		{
+			name: "Widths too small for the text",
+			args: args{
+				diffs: Diff([]string{"ab"}, []string{"x"}),
+				opts:  SideBySideOptions{Width: 5, LineNumbers: true, Wrap: true},
+			},
+			want: "1 a | 1 x\n" +
+				"  b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SideBySideTextWithOptions(tt.args.diffs, tt.args.opts); got != tt.want {
+				t.Errorf("SideBySideTextWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,13 +7,31 @@ type Hunk struct {
 	SrcLines int
 	DstStart int
 	DstLines int
+	// Section is the optional text following the line ranges of a hunk
+	// header, such as the enclosing function name.
+	Section string
 }
 
-// makeHunks returns the hunks of a diff.
-func makeHunks(diffs []DiffLine, precontext, postcontext int) []Hunk {
+// HunkOptions represents the options for HunksWithOptions.
+type HunkOptions struct {
+	// Precontext is the number of lines of context before each change in a hunk.
+	Precontext int
+	// Postcontext is the number of lines of context after each change in a hunk.
+	Postcontext int
+	// IgnoreBlankLines omits hunks whose changed lines are all empty (diff -B).
+	// Empty changes within a hunk that has other changes are kept.
+	IgnoreBlankLines bool
+}
+
+// Hunks returns the hunks of a diff, with up to precontext and postcontext
+// equal lines before and after each change. Changes separated by no more
+// than precontext+postcontext equal lines are combined into one hunk.
+// Negative context is treated as no context.
+func Hunks(diffs []DiffLine, precontext, postcontext int) []Hunk {
 	if len(diffs) == 0 {
 		return nil
 	}
+	precontext, postcontext = max(precontext, 0), max(postcontext, 0)
 
 	hunks := []Hunk{}
 
@@ -139,6 +157,16 @@ func makeHunks(diffs []DiffLine, precontext, postcontext int) []Hunk {
 		return nil
 	}
 
+	// As in GNU diff, an empty line range starts at the line before it.
+	for i := range hunks {
+		if hunks[i].SrcLines == 0 && hunks[i].SrcStart > 0 {
+			hunks[i].SrcStart--
+		}
+		if hunks[i].DstLines == 0 && hunks[i].DstStart > 0 {
+			hunks[i].DstStart--
+		}
+	}
+
 	return hunks
 }
 
@@ -150,3 +178,28 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// blankHunk returns true if every changed line of a hunk is empty.
+func blankHunk(h Hunk) bool {
+	for _, l := range h.Diffs {
+		if l.Type != Equal && len(l.Text) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// HunksWithOptions returns the hunks of a diff as specified by the options.
+func HunksWithOptions(diffs []DiffLine, opts HunkOptions) []Hunk {
+	hunks := Hunks(diffs, opts.Precontext, opts.Postcontext)
+	if !opts.IgnoreBlankLines || hunks == nil {
+		return hunks
+	}
+	out := []Hunk{}
+	for _, h := range hunks {
+		if !blankHunk(h) {
+			out = append(out, h)
+		}
+	}
+	return out
+}
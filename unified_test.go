@@ -5,7 +5,7 @@ import (
 	"testing"
 )
 
-func Test_makeHunks(t *testing.T) {
+func TestHunks(t *testing.T) {
 	e := DiffLine{
 		Type: Equal,
 		Text: "e",
@@ -345,6 +345,39 @@ func Test_makeHunks(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Test empty line ranges with no context",
+			args: args{
+				diffs: []DiffLine{
+					i, e, e, e, e, d, e, e, e, e, i,
+				},
+				precontext:  0,
+				postcontext: 0,
+			},
+			want: []Hunk{
+				{
+					Diffs:    []DiffLine{i},
+					SrcStart: 0,
+					SrcLines: 0,
+					DstStart: 1,
+					DstLines: 1,
+				},
+				{
+					Diffs:    []DiffLine{d},
+					SrcStart: 5,
+					SrcLines: 1,
+					DstStart: 5,
+					DstLines: 0,
+				},
+				{
+					Diffs:    []DiffLine{i},
+					SrcStart: 9,
+					SrcLines: 0,
+					DstStart: 10,
+					DstLines: 1,
+				},
+			},
+		},
 		{
 			name: "Test equal block head/tail content",
 			args: args{
@@ -404,12 +437,81 @@ func Test_makeHunks(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := makeHunks(
+			if got := Hunks(
 				tt.args.diffs,
 				tt.args.precontext,
 				tt.args.postcontext,
 			); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("makeHunks() = %v, want %v", got, tt.want)
+				t.Errorf("Hunks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHunksNegativeContext tests that negative context is treated as no context.
+func TestHunksNegativeContext(t *testing.T) {
+	diffs := Diff([]string{"a", "b", "c", "d", "e"}, []string{"a", "x", "c", "d", "e", "y"})
+	want := Hunks(diffs, 0, 0)
+	for _, ctx := range [][2]int{{-1, 0}, {0, -1}, {-3, -3}} {
+		if got := Hunks(diffs, ctx[0], ctx[1]); !reflect.DeepEqual(got, want) {
+			t.Errorf("Hunks(%d, %d) = %v, want %v", ctx[0], ctx[1], got, want)
+		}
+	}
+}
+
+func TestHunksWithOptions(t *testing.T) {
+	type args struct {
+		a    []string
+		b    []string
+		opts HunkOptions
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Test no options",
+			args: args{
+				a:    []string{"a", "b", "c", "d", "e", "f", "g"},
+				b:    []string{"", "a", "b", "c", "d", "x", "f", "g"},
+				opts: HunkOptions{Precontext: 1, Postcontext: 1},
+			},
+			want: "@@ -1,1 +1,2 @@\n+\n a\n@@ -4,3 +5,3 @@\n d\n-e\n+x\n f",
+		},
+		{
+			name: "Test ignore blank lines omits blank hunks",
+			args: args{
+				a:    []string{"a", "b", "c", "d", "e", "f", "g"},
+				b:    []string{"", "a", "b", "c", "d", "x", "f", "g"},
+				opts: HunkOptions{Precontext: 1, Postcontext: 1, IgnoreBlankLines: true},
+			},
+			want: "@@ -4,3 +5,3 @@\n d\n-e\n+x\n f",
+		},
+		{
+			name: "Test ignore blank lines keeps blank changes in other hunks",
+			args: args{
+				a:    []string{"a", "b", "c", "d", "e"},
+				b:    []string{"a", "b", "", "c", "x", "e"},
+				opts: HunkOptions{Precontext: 1, Postcontext: 1, IgnoreBlankLines: true},
+			},
+			want: "@@ -2,4 +2,5 @@\n b\n+\n c\n-d\n+x\n e",
+		},
+		{
+			name: "Test ignore blank lines keeps white space lines",
+			args: args{
+				a:    []string{"a", "b", "c", "d", "e", "f", "g"},
+				b:    []string{"a", " \t", "b", "c", "d", "e", "f", "g"},
+				opts: HunkOptions{Precontext: 1, Postcontext: 1, IgnoreBlankLines: true},
+			},
+			want: "@@ -1,2 +1,3 @@\n a\n+ \t\n b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks := HunksWithOptions(Diff(tt.args.a, tt.args.b), tt.args.opts)
+			if got := UnifiedHunksText(hunks, UnifiedDiffOptions{}); got != tt.want {
+				t.Errorf("HunksWithOptions() = %q, want %q", got, tt.want)
 			}
 		})
 	}
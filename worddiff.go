@@ -0,0 +1,113 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import "regexp"
+
+// Segment represents a part of the text of a line and its diff type.
+type Segment struct {
+	Text string
+	Type DiffType
+}
+
+// wordTokenizer matches words, runs of white space, and single characters
+// that are neither.
+var wordTokenizer = regexp.MustCompile(`[\p{L}\p{N}_]+|\s+|[^\p{L}\p{N}_\s]`)
+
+// WordDiffOptions represents the options for WordDiffWithOptions.
+type WordDiffOptions struct {
+	// Tokenizer matches the tokens that lines are divided into. Text between
+	// matches is also a token. If nil, lines are divided into words, runs of
+	// white space and punctuation. Use regexp.MustCompile(".") to diff
+	// individual characters.
+	Tokenizer *regexp.Regexp
+}
+
+// tokenize divides a string into tokens matched by a regular expression,
+// and the text between matches.
+func tokenize(re *regexp.Regexp, s string) []string {
+	tokens := []string{}
+	i := 0
+	for _, m := range re.FindAllStringIndex(s, -1) {
+		if m[0] == m[1] {
+			continue
+		}
+		if m[0] > i {
+			tokens = append(tokens, s[i:m[0]])
+		}
+		tokens = append(tokens, s[m[0]:m[1]])
+		i = m[1]
+	}
+	if i < len(s) {
+		tokens = append(tokens, s[i:])
+	}
+	return tokens
+}
+
+// appendSegment appends text to a slice of segments, merging it with the
+// last segment if it has the same diff type.
+func appendSegment(segments []Segment, text string, t DiffType) []Segment {
+	if n := len(segments); n > 0 && segments[n-1].Type == t {
+		segments[n-1].Text += text
+		return segments
+	}
+	return append(segments, Segment{Text: text, Type: t})
+}
+
+// wordSegments returns the segments of a deleted line and an inserted line.
+func wordSegments(re *regexp.Regexp, del, ins string) ([]Segment, []Segment) {
+	delSegments, insSegments := []Segment{}, []Segment{}
+	for _, e := range DiffSlices(tokenize(re, del), tokenize(re, ins)) {
+		if e.Type != Insert {
+			delSegments = appendSegment(delSegments, e.Value, e.Type)
+		}
+		if e.Type != Delete {
+			insSegments = appendSegment(insSegments, e.Value, e.Type)
+		}
+	}
+	return delSegments, insSegments
+}
+
+// WordDiffWithOptions returns a copy of a diff with the Segments of changed
+// lines populated. Within each block of adjacent deletions and insertions,
+// the nth deleted line is paired with the nth inserted line, and the tokens
+// of each pair are diffed. Lines without a pair are left without segments.
+func WordDiffWithOptions(diffs []DiffLine, opts WordDiffOptions) []DiffLine {
+	if diffs == nil {
+		return nil
+	}
+	re := opts.Tokenizer
+	if re == nil {
+		re = wordTokenizer
+	}
+
+	out := make([]DiffLine, len(diffs))
+	copy(out, diffs)
+	for i := 0; i < len(out); {
+		if out[i].Type == Equal {
+			i++
+			continue
+		}
+
+		// Collect the deletions and insertions of the block of changes.
+		dels, inss := []int{}, []int{}
+		for ; i < len(out) && out[i].Type != Equal; i++ {
+			if out[i].Type == Delete {
+				dels = append(dels, i)
+			} else {
+				inss = append(inss, i)
+			}
+		}
+
+		for k := 0; k < len(dels) && k < len(inss); k++ {
+			d, n := dels[k], inss[k]
+			out[d].Segments, out[n].Segments = wordSegments(re, out[d].Text, out[n].Text)
+		}
+	}
+	return out
+}
+
+// WordDiff returns a copy of a diff with the Segments of changed lines
+// populated from a diff of the words of each pair of changed lines.
+func WordDiff(diffs []DiffLine) []DiffLine {
+	return WordDiffWithOptions(diffs, WordDiffOptions{})
+}
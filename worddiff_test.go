@@ -0,0 +1,145 @@
+// Package patience implements the Patience Diff algorithm.
+package patience
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func Test_tokenize(t *testing.T) {
+	type args struct {
+		re *regexp.Regexp
+		s  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "Test empty string",
+			args: args{
+				re: wordTokenizer,
+				s:  "",
+			},
+			want: []string{},
+		},
+		{
+			name: "Test words",
+			args: args{
+				re: wordTokenizer,
+				s:  "fmt.Println(x,  y)",
+			},
+			want: []string{"fmt", ".", "Println", "(", "x", ",", "  ", "y", ")"},
+		},
+		{
+			name: "Test text between matches",
+			args: args{
+				re: regexp.MustCompile(`\d+`),
+				s:  "a1bc23",
+			},
+			want: []string{"a", "1", "bc", "23"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenize(tt.args.re, tt.args.s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordDiff(t *testing.T) {
+	type args struct {
+		diffs []DiffLine
+	}
+	tests := []struct {
+		name string
+		args args
+		want []DiffLine
+	}{
+		{
+			name: "Test nil diffs",
+			args: args{
+				diffs: nil,
+			},
+			want: nil,
+		},
+		{
+			name: "Test paired lines",
+			args: args{
+				diffs: []DiffLine{
+					{Text: "a", Type: Equal},
+					{Text: "the quick fox", Type: Delete},
+					{Text: "the slow fox", Type: Insert},
+					{Text: "jumps", Type: Insert},
+					{Text: "b", Type: Equal},
+				},
+			},
+			want: []DiffLine{
+				{Text: "a", Type: Equal},
+				{
+					Text: "the quick fox",
+					Type: Delete,
+					Segments: []Segment{
+						{Text: "the ", Type: Equal},
+						{Text: "quick", Type: Delete},
+						{Text: " fox", Type: Equal},
+					},
+				},
+				{
+					Text: "the slow fox",
+					Type: Insert,
+					Segments: []Segment{
+						{Text: "the ", Type: Equal},
+						{Text: "slow", Type: Insert},
+						{Text: " fox", Type: Equal},
+					},
+				},
+				{Text: "jumps", Type: Insert},
+				{Text: "b", Type: Equal},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WordDiff(tt.args.diffs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WordDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordDiffWithOptions(t *testing.T) {
+	diffs := []DiffLine{
+		{Text: "color", Type: Delete},
+		{Text: "colour", Type: Insert},
+	}
+	want := []DiffLine{
+		{
+			Text: "color",
+			Type: Delete,
+			Segments: []Segment{
+				{Text: "color", Type: Equal},
+			},
+		},
+		{
+			Text: "colour",
+			Type: Insert,
+			Segments: []Segment{
+				{Text: "colo", Type: Equal},
+				{Text: "u", Type: Insert},
+				{Text: "r", Type: Equal},
+			},
+		},
+	}
+	got := WordDiffWithOptions(diffs, WordDiffOptions{Tokenizer: regexp.MustCompile(".")})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WordDiffWithOptions() = %v, want %v", got, want)
+	}
+	if diffs[0].Segments != nil {
+		t.Errorf("WordDiffWithOptions() modified the diffs")
+	}
+}